@@ -7,7 +7,7 @@ import (
 	"time"
 )
 
-//go:generate go tool mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ChargeRater,Battery,Tariff,BatteryController,Circuit
+//go:generate go tool mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ChargeRater,Battery,Tariff,BatteryController,BatteryPowerController,PowerLimiter,Circuit
 
 // Meter provides total active power in W
 type Meter interface {
@@ -49,6 +49,12 @@ type MaxACPowerGetter interface {
 	MaxACPower() float64
 }
 
+// PowerLimiter optionally allows to limit the inverter's active power output, e.g. for feed-in curtailment
+type PowerLimiter interface {
+	// SetPowerLimit sets the max active power output in W. A negative limit removes the limit.
+	SetPowerLimit(float64) error
+}
+
 // ChargeState provides current charging status
 type ChargeState interface {
 	Status() (ChargeStatus, error)
@@ -73,6 +79,13 @@ type BatteryController interface {
 	SetBatteryMode(BatteryMode) error
 }
 
+// BatteryPowerController optionally allows to control home battery (dis)charging power.
+// Normal operation is resumed using BatteryController.
+type BatteryPowerController interface {
+	// SetBatteryPower sets the battery power setpoint in W (charge negative, discharge positive)
+	SetBatteryPower(float64) error
+}
+
 // Charger provides current charging status and enable/disable charging
 type Charger interface {
 	ChargeState
@@ -245,7 +258,14 @@ type Circuit interface {
 	SetMaxCurrent(float64)
 	Update([]CircuitLoad) error
 	ValidateCurrent(old, new float64) float64
+	ValidatePhaseCurrent(phases []int, old, new float64) float64
 	ValidatePower(old, new float64) float64
+	ValidateImbalance(phases []int, old, new float64) float64
+	Distribute([]CircuitLoad)
+	GetAllocation(load CircuitLoad) (float64, bool)
+	Shedding() bool
+	Shed(phases []int, current, power float64)
+	GetOverloads() int
 }
 
 // Redactor is an interface to redact sensitive data
@@ -0,0 +1,25 @@
+package api
+
+// CapabilityProvider provides optional capabilities which are not decorated onto the device itself.
+// This keeps the number of generated decorator combinations small.
+type CapabilityProvider interface {
+	Capabilities() []any
+}
+
+// Capability returns the device's capability T, either implemented by the device or by one of its provided capabilities
+func Capability[T any](dev any) (T, bool) {
+	if res, ok := dev.(T); ok {
+		return res, true
+	}
+
+	if cp, ok := dev.(CapabilityProvider); ok {
+		for _, c := range cp.Capabilities() {
+			if res, ok := c.(T); ok {
+				return res, true
+			}
+		}
+	}
+
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type capabilityProvider struct {
+	Meter
+	capabilities []any
+}
+
+func (p *capabilityProvider) Capabilities() []any {
+	return p.capabilities
+}
+
+func TestCapability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// implemented by the device
+	batPower := NewMockBatteryPowerController(ctrl)
+	res, ok := Capability[BatteryPowerController](batPower)
+	assert.True(t, ok)
+	assert.Equal(t, batPower, res)
+
+	// provided by the device
+	dev := &capabilityProvider{Meter: NewMockMeter(ctrl)}
+	_, ok = Capability[BatteryPowerController](dev)
+	assert.False(t, ok)
+
+	dev.capabilities = append(dev.capabilities, batPower)
+	res, ok = Capability[BatteryPowerController](dev)
+	assert.True(t, ok)
+	assert.Equal(t, batPower, res)
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ChargeRater,Battery,Tariff,BatteryController,Circuit)
+// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ChargeRater,Battery,Tariff,BatteryController,BatteryPowerController,PowerLimiter,Circuit)
 //
 // Generated by this command:
 //
-//	mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ChargeRater,Battery,Tariff,BatteryController,Circuit
+//	mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ChargeRater,Battery,Tariff,BatteryController,BatteryPowerController,PowerLimiter,Circuit
 //
 
 // Package api is a generated GoMock package.
@@ -806,6 +806,82 @@ func (mr *MockBatteryControllerMockRecorder) SetBatteryMode(arg0 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBatteryMode", reflect.TypeOf((*MockBatteryController)(nil).SetBatteryMode), arg0)
 }
 
+// MockBatteryPowerController is a mock of BatteryPowerController interface.
+type MockBatteryPowerController struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatteryPowerControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockBatteryPowerControllerMockRecorder is the mock recorder for MockBatteryPowerController.
+type MockBatteryPowerControllerMockRecorder struct {
+	mock *MockBatteryPowerController
+}
+
+// NewMockBatteryPowerController creates a new mock instance.
+func NewMockBatteryPowerController(ctrl *gomock.Controller) *MockBatteryPowerController {
+	mock := &MockBatteryPowerController{ctrl: ctrl}
+	mock.recorder = &MockBatteryPowerControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatteryPowerController) EXPECT() *MockBatteryPowerControllerMockRecorder {
+	return m.recorder
+}
+
+// SetBatteryPower mocks base method.
+func (m *MockBatteryPowerController) SetBatteryPower(arg0 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBatteryPower", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBatteryPower indicates an expected call of SetBatteryPower.
+func (mr *MockBatteryPowerControllerMockRecorder) SetBatteryPower(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBatteryPower", reflect.TypeOf((*MockBatteryPowerController)(nil).SetBatteryPower), arg0)
+}
+
+// MockPowerLimiter is a mock of PowerLimiter interface.
+type MockPowerLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPowerLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockPowerLimiterMockRecorder is the mock recorder for MockPowerLimiter.
+type MockPowerLimiterMockRecorder struct {
+	mock *MockPowerLimiter
+}
+
+// NewMockPowerLimiter creates a new mock instance.
+func NewMockPowerLimiter(ctrl *gomock.Controller) *MockPowerLimiter {
+	mock := &MockPowerLimiter{ctrl: ctrl}
+	mock.recorder = &MockPowerLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPowerLimiter) EXPECT() *MockPowerLimiterMockRecorder {
+	return m.recorder
+}
+
+// SetPowerLimit mocks base method.
+func (m *MockPowerLimiter) SetPowerLimit(arg0 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPowerLimit", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPowerLimit indicates an expected call of SetPowerLimit.
+func (mr *MockPowerLimiterMockRecorder) SetPowerLimit(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPowerLimit", reflect.TypeOf((*MockPowerLimiter)(nil).SetPowerLimit), arg0)
+}
+
 // MockCircuit is a mock of Circuit interface.
 type MockCircuit struct {
 	ctrl     *gomock.Controller
@@ -830,6 +906,33 @@ func (m *MockCircuit) EXPECT() *MockCircuitMockRecorder {
 	return m.recorder
 }
 
+// Distribute mocks base method.
+func (m *MockCircuit) Distribute(arg0 []CircuitLoad) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Distribute", arg0)
+}
+
+// Distribute indicates an expected call of Distribute.
+func (mr *MockCircuitMockRecorder) Distribute(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Distribute", reflect.TypeOf((*MockCircuit)(nil).Distribute), arg0)
+}
+
+// GetAllocation mocks base method.
+func (m *MockCircuit) GetAllocation(load CircuitLoad) (float64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllocation", load)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetAllocation indicates an expected call of GetAllocation.
+func (mr *MockCircuitMockRecorder) GetAllocation(load any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllocation", reflect.TypeOf((*MockCircuit)(nil).GetAllocation), load)
+}
+
 // GetChargePower mocks base method.
 func (m *MockCircuit) GetChargePower() float64 {
 	m.ctrl.T.Helper()
@@ -886,6 +989,20 @@ func (mr *MockCircuitMockRecorder) GetMaxPower() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxPower", reflect.TypeOf((*MockCircuit)(nil).GetMaxPower))
 }
 
+// GetOverloads mocks base method.
+func (m *MockCircuit) GetOverloads() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverloads")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetOverloads indicates an expected call of GetOverloads.
+func (mr *MockCircuitMockRecorder) GetOverloads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverloads", reflect.TypeOf((*MockCircuit)(nil).GetOverloads))
+}
+
 // GetParent mocks base method.
 func (m *MockCircuit) GetParent() Circuit {
 	m.ctrl.T.Helper()
@@ -976,6 +1093,32 @@ func (mr *MockCircuitMockRecorder) SetTitle(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTitle", reflect.TypeOf((*MockCircuit)(nil).SetTitle), arg0)
 }
 
+// Shed mocks base method.
+func (m *MockCircuit) Shed(phases []int, current, power float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shed", phases, current, power)
+}
+
+// Shed indicates an expected call of Shed.
+func (mr *MockCircuitMockRecorder) Shed(phases, current, power any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shed", reflect.TypeOf((*MockCircuit)(nil).Shed), phases, current, power)
+}
+
+// Shedding mocks base method.
+func (m *MockCircuit) Shedding() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shedding")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Shedding indicates an expected call of Shedding.
+func (mr *MockCircuitMockRecorder) Shedding() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shedding", reflect.TypeOf((*MockCircuit)(nil).Shedding))
+}
+
 // Update mocks base method.
 func (m *MockCircuit) Update(arg0 []CircuitLoad) error {
 	m.ctrl.T.Helper()
@@ -1004,6 +1147,34 @@ func (mr *MockCircuitMockRecorder) ValidateCurrent(old, new any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCurrent", reflect.TypeOf((*MockCircuit)(nil).ValidateCurrent), old, new)
 }
 
+// ValidateImbalance mocks base method.
+func (m *MockCircuit) ValidateImbalance(phases []int, old, new float64) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateImbalance", phases, old, new)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// ValidateImbalance indicates an expected call of ValidateImbalance.
+func (mr *MockCircuitMockRecorder) ValidateImbalance(phases, old, new any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateImbalance", reflect.TypeOf((*MockCircuit)(nil).ValidateImbalance), phases, old, new)
+}
+
+// ValidatePhaseCurrent mocks base method.
+func (m *MockCircuit) ValidatePhaseCurrent(phases []int, old, new float64) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePhaseCurrent", phases, old, new)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// ValidatePhaseCurrent indicates an expected call of ValidatePhaseCurrent.
+func (mr *MockCircuitMockRecorder) ValidatePhaseCurrent(phases, old, new any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePhaseCurrent", reflect.TypeOf((*MockCircuit)(nil).ValidatePhaseCurrent), phases, old, new)
+}
+
 // ValidatePower mocks base method.
 func (m *MockCircuit) ValidatePower(old, new float64) float64 {
 	m.ctrl.T.Helper()
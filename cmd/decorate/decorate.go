@@ -41,6 +41,7 @@ var a struct {
 	api.PhaseVoltages
 	api.PhasePowers
 	api.MaxACPowerGetter
+	api.PowerLimiter
 
 	api.PhaseSwitcher
 	api.PhaseGetter
@@ -48,6 +49,7 @@ var a struct {
 	api.Battery
 	api.BatteryCapacity
 	api.BatteryController
+	api.BatteryPowerController
 	api.SocLimiter
 }
 
@@ -56,10 +58,10 @@ func typ(i any) string {
 }
 
 var dependents = map[string][]string{
-	typ(&a.Meter):         {typ(&a.MeterEnergy), typ(&a.PhaseCurrents), typ(&a.PhaseVoltages), typ(&a.PhasePowers), typ(&a.MaxACPowerGetter)},
+	typ(&a.Meter):         {typ(&a.MeterEnergy), typ(&a.PhaseCurrents), typ(&a.PhaseVoltages), typ(&a.PhasePowers), typ(&a.MaxACPowerGetter), typ(&a.PowerLimiter)},
 	typ(&a.PhaseCurrents): {typ(&a.PhasePowers)}, // phase powers are only used to determine currents sign
 	typ(&a.PhaseSwitcher): {typ(&a.PhaseGetter)},
-	typ(&a.Battery):       {typ(&a.BatteryCapacity), typ(&a.BatteryController), typ(&a.SocLimiter)},
+	typ(&a.Battery):       {typ(&a.BatteryCapacity), typ(&a.BatteryController), typ(&a.BatteryPowerController), typ(&a.SocLimiter)},
 }
 
 // hasIntersection returns if the slices intersect
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cobra"
+)
+
+// socTuneCmd represents the soc-tune command
+var socTuneCmd = &cobra.Command{
+	Use:   "soc-tune [csv file...]",
+	Short: "Replay recorded charging curves to tune charging speed-based soc estimation",
+	Long: `Replays recorded charging curves through the charging speed-based soc estimator
+for a grid of configuration values and reports when each configuration would have stopped charging.
+Curves are read from csv files (timestamp and power columns, e.g. exported from /api/sessions/{id}/curve
+or an external meter log) or from the session database.`,
+	Run: runSocTune,
+}
+
+const (
+	flagSocTuneSession         = "session"
+	flagSocTuneTargetSoc       = "target-soc"
+	flagSocTuneThreshold       = "threshold"
+	flagSocTuneMinChargingTime = "min-charging-time"
+	flagSocTuneStabilityWindow = "stability-window"
+	flagSocTuneMaxPowerWindow  = "max-power-window"
+	flagSocTuneMinPower        = "min-power"
+	flagSocTuneSampleInterval  = "sample-interval"
+	flagSocTuneVoltage         = "voltage"
+)
+
+func init() {
+	rootCmd.AddCommand(socTuneCmd)
+
+	def := soc.DefaultChargingSpeedConfig()
+
+	socTuneCmd.Flags().UintSlice(flagSocTuneSession, nil, "Replay session(s) from the session database")
+	socTuneCmd.Flags().IntSlice(flagSocTuneTargetSoc, []int{def.TargetSoc}, "Target soc (%)")
+	socTuneCmd.Flags().Float64Slice(flagSocTuneThreshold, []float64{0.1, def.ReductionThreshold, 0.2}, "Power reduction threshold (0..1)")
+	socTuneCmd.Flags().DurationSlice(flagSocTuneMinChargingTime, []time.Duration{def.MinChargingTime}, "Minimum charging time before estimation")
+	socTuneCmd.Flags().DurationSlice(flagSocTuneStabilityWindow, []time.Duration{def.StabilityWindow}, "Stable power reduction window")
+	socTuneCmd.Flags().DurationSlice(flagSocTuneMaxPowerWindow, []time.Duration{def.MaxPowerWindow}, "Max power window")
+	socTuneCmd.Flags().Float64Slice(flagSocTuneMinPower, []float64{def.MinPowerForEstimation}, "Minimum power for estimation (W)")
+	socTuneCmd.Flags().Duration(flagSocTuneSampleInterval, def.SampleInterval, "Sample interval")
+	socTuneCmd.Flags().Float64(flagSocTuneVoltage, 230, "Voltage for converting offered current to power")
+}
+
+// socTuneCurve is a named recorded charging curve
+type socTuneCurve struct {
+	name    string
+	samples []soc.ReplaySample
+}
+
+func runSocTune(cmd *cobra.Command, args []string) {
+	flags := cmd.Flags()
+	sessions, _ := flags.GetUintSlice(flagSocTuneSession)
+	voltage, _ := flags.GetFloat64(flagSocTuneVoltage)
+
+	if len(args) == 0 && len(sessions) == 0 {
+		log.FATAL.Fatal("missing csv file or session")
+	}
+
+	var curves []socTuneCurve
+
+	for _, name := range args {
+		f, err := os.Open(name)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		samples, err := readReplayCsv(f, voltage)
+		f.Close()
+		if err != nil {
+			log.FATAL.Fatalf("%s: %v", name, err)
+		}
+
+		curves = append(curves, socTuneCurve{name, samples})
+	}
+
+	if len(sessions) > 0 {
+		// load config
+		if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		// setup persistence
+		if err := configureDatabase(conf.Database); err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		store, err := session.NewStore("", db.Instance)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		for _, id := range sessions {
+			curve, err := store.Curve(id)
+			if err != nil {
+				log.FATAL.Fatal(err)
+			}
+
+			curves = append(curves, socTuneCurve{fmt.Sprintf("session %d", id), sessionReplaySamples(curve, voltage)})
+		}
+	}
+
+	grid := socTuneGrid(cmd)
+	logger := util.NewLogger("soc-tune")
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Curve\tTarget\tThreshold\tMinCharging\tStability\tMaxPowerWindow\tMinPower\tEstimation\tStop\tDuration\tEnergy (kWh)\tSoc (%)")
+	const format = "15:04:05"
+
+	for _, c := range curves {
+		if len(c.samples) == 0 {
+			fmt.Fprintf(tw, "%s\tno samples\n", c.name)
+			continue
+		}
+
+		for _, cc := range grid {
+			res := soc.Replay(logger, cc, c.samples)
+
+			estimation, stop := "-", "-"
+			if !res.EstimationStart.IsZero() {
+				estimation = res.EstimationStart.Local().Format(format)
+			}
+			if res.Stopped {
+				stop = res.Stop.Local().Format(format)
+			}
+
+			fmt.Fprintf(tw, "%s\t%d\t%.2f\t%v\t%v\t%v\t%.0f\t%s\t%s\t%v\t%.2f\t%.1f\n",
+				c.name, cc.TargetSoc, cc.ReductionThreshold, cc.MinChargingTime, cc.StabilityWindow, cc.MaxPowerWindow,
+				cc.MinPowerForEstimation, estimation, stop, res.Duration.Round(time.Minute), res.Energy/1e3, res.EstimatedSoc)
+		}
+	}
+
+	tw.Flush()
+}
+
+// socTuneGrid returns all combinations of the configured parameter values
+func socTuneGrid(cmd *cobra.Command) []soc.ChargingSpeedConfig {
+	flags := cmd.Flags()
+
+	targetSocs, _ := flags.GetIntSlice(flagSocTuneTargetSoc)
+	thresholds, _ := flags.GetFloat64Slice(flagSocTuneThreshold)
+	minChargingTimes, _ := flags.GetDurationSlice(flagSocTuneMinChargingTime)
+	stabilityWindows, _ := flags.GetDurationSlice(flagSocTuneStabilityWindow)
+	maxPowerWindows, _ := flags.GetDurationSlice(flagSocTuneMaxPowerWindow)
+	minPowers, _ := flags.GetFloat64Slice(flagSocTuneMinPower)
+	sampleInterval, _ := flags.GetDuration(flagSocTuneSampleInterval)
+
+	var res []soc.ChargingSpeedConfig
+
+	for _, targetSoc := range targetSocs {
+		for _, threshold := range thresholds {
+			for _, minChargingTime := range minChargingTimes {
+				for _, stabilityWindow := range stabilityWindows {
+					for _, maxPowerWindow := range maxPowerWindows {
+						for _, minPower := range minPowers {
+							cc := soc.DefaultChargingSpeedConfig()
+							cc.TargetSoc = targetSoc
+							cc.ReductionThreshold = threshold
+							cc.MinChargingTime = minChargingTime
+							cc.StabilityWindow = stabilityWindow
+							cc.MaxPowerWindow = maxPowerWindow
+							cc.MinPowerForEstimation = minPower
+							cc.SampleInterval = sampleInterval
+
+							res = append(res, cc)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// sessionReplaySamples converts a session charging curve to replay samples
+func sessionReplaySamples(curve session.Curve, voltage float64) []soc.ReplaySample {
+	res := make([]soc.ReplaySample, 0, len(curve))
+	for _, p := range curve {
+		res = append(res, soc.ReplaySample{
+			Timestamp: p.Timestamp,
+			Power:     p.Power,
+			Offered:   p.OfferedCurrent * float64(p.Phases) * voltage,
+		})
+	}
+	return res
+}
+
+// readReplayCsv reads a recorded charging curve. Timestamp and power columns are required,
+// offered current and phases are optional. Semicolon separated files use decimal commas.
+func readReplayCsv(r io.Reader, voltage float64) ([]soc.ReplaySample, error) {
+	br := bufio.NewReader(r)
+
+	// skip byte order mark
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(3)
+	}
+
+	header, err := br.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	comma := ','
+	if strings.Contains(header, ";") {
+		comma = ';'
+	}
+
+	cr := csv.NewReader(io.MultiReader(strings.NewReader(header), br))
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty file")
+	}
+
+	column := func(names ...string) int {
+		return slices.IndexFunc(records[0], func(s string) bool {
+			s = strings.ToLower(s)
+			return slices.ContainsFunc(names, func(name string) bool {
+				return strings.Contains(s, name)
+			})
+		})
+	}
+
+	timeCol, powerCol := column("time", "date"), column("power", "leistung")
+	offeredCol, phasesCol := column("offered"), column("phase")
+
+	if timeCol < 0 || powerCol < 0 {
+		return nil, errors.New("missing timestamp or power column")
+	}
+
+	parseFloat := func(s string) (float64, error) {
+		if comma == ';' {
+			s = strings.ReplaceAll(s, ",", ".")
+		}
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+
+	var res []soc.ReplaySample
+
+	for i, rec := range records[1:] {
+		if len(rec) <= max(timeCol, powerCol) {
+			continue
+		}
+
+		ts, err := parseReplayTime(rec[timeCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		power, err := parseFloat(rec[powerCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		s := soc.ReplaySample{
+			Timestamp: ts,
+			Power:     power,
+		}
+
+		if offeredCol >= 0 && offeredCol < len(rec) {
+			phases := 3.0
+			if phasesCol >= 0 && phasesCol < len(rec) {
+				if f, err := parseFloat(rec[phasesCol]); err == nil && f > 0 {
+					phases = f
+				}
+			}
+
+			if current, err := parseFloat(rec[offeredCol]); err == nil {
+				s.Offered = current * phases * voltage
+			}
+		}
+
+		res = append(res, s)
+	}
+
+	slices.SortFunc(res, func(a, b soc.ReplaySample) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return res, nil
+}
+
+// parseReplayTime parses RFC3339, local date time or unix timestamps
+func parseReplayTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if ts, err := time.Parse(time.RFC3339, s); err == nil {
+		return ts, nil
+	}
+
+	if ts, err := time.ParseInLocation(time.DateTime, s, time.Local); err == nil {
+		return ts, nil
+	}
+
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s", s)
+}
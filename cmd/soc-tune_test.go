@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReplayCsv(t *testing.T) {
+	// session curve export, german locale
+	data := "\xEF\xBB\xBFTimestamp;Power (W);Phases;Offered Current (A);SoC (%);Estimated SoC (%)\n" +
+		"2025-01-01 12:00:30;10500;3;16;;\n" +
+		"2025-01-01 12:00:00;11000,5;3;16;;\n"
+
+	samples, err := readReplayCsv(strings.NewReader(data), 230)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+
+	assert.Equal(t, 11000.5, samples[0].Power)
+	assert.Equal(t, 16*3*230.0, samples[0].Offered)
+	assert.Equal(t, 30*time.Second, samples[1].Timestamp.Sub(samples[0].Timestamp))
+
+	// external meter log
+	data = "time,power\n1735732800,7000\n1735732830,6900\n"
+
+	samples, err = readReplayCsv(strings.NewReader(data), 230)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, 0.0, samples[0].Offered)
+
+	_, err = readReplayCsv(strings.NewReader("foo,bar\n1,2\n"), 230)
+	assert.Error(t, err)
+}
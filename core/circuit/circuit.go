@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -28,13 +29,25 @@ type Circuit struct {
 	meter    api.Meter     // meter to determine current power
 	timeout  time.Duration
 
+	shedding     bool          // actively reduce loads on overload
+	pollInterval time.Duration // meter polling interval for fast overload detection
+
 	maxCurrent    float64                 // max allowed current
 	maxPower      float64                 // max allowed power
+	maxImbalance  float64                 // max allowed current difference between phases
+	policy        Policy                  // distribution policy among consumers
 	getMaxCurrent func() (float64, error) // dynamic max allowed current
 	getMaxPower   func() (float64, error) // dynamic max allowed power
 
-	current float64
-	power   float64
+	current  float64
+	currents [3]float64 // per-phase currents
+	power    float64
+
+	overloaded bool // circuit exceeds its limits
+	overloads  int  // number of detected overloads
+	relieved   bool // overload relieved by shedding until the next measurement
+
+	allocations map[api.CircuitLoad]float64 // current allocated to consumers by the policy
 
 	currentUpdated time.Time
 	powerUpdated   time.Time
@@ -48,9 +61,13 @@ func NewFromConfig(ctx context.Context, log *util.Logger, other map[string]inter
 		MeterRef      string         `mapstructure:"meter"`  // meter reference
 		MaxCurrent    float64        // the max allowed current
 		MaxPower      float64        // the max allowed power
+		MaxImbalance  float64        // the max allowed current difference between phases
+		Policy        string         // distribution policy among loadpoints
 		GetMaxCurrent *plugin.Config // dynamic max allowed current
 		GetMaxPower   *plugin.Config // dynamic max allowed power
 		Timeout       time.Duration  // timeout between meter updates
+		Shedding      bool           // actively reduce loadpoints on overload
+		PollInterval  time.Duration  // meter polling interval for fast overload detection
 	}{
 		Timeout: time.Minute,
 	}
@@ -73,6 +90,26 @@ func NewFromConfig(ctx context.Context, log *util.Logger, other map[string]inter
 		return nil, err
 	}
 
+	if cc.MaxImbalance > 0 {
+		if _, ok := meter.(api.PhaseCurrents); meter != nil && !ok {
+			return nil, fmt.Errorf("meter does not support phase currents")
+		}
+		circuit.maxImbalance = cc.MaxImbalance
+	}
+
+	if circuit.policy, err = NewPolicy(cc.Policy); err != nil {
+		return nil, err
+	}
+
+	circuit.shedding = cc.Shedding
+
+	if cc.PollInterval > 0 {
+		if meter == nil || !cc.Shedding {
+			return nil, fmt.Errorf("poll interval requires meter and shedding")
+		}
+		circuit.pollInterval = cc.PollInterval
+	}
+
 	circuit.getMaxPower, err = cc.GetMaxPower.FloatGetter(ctx)
 	if err != nil {
 		return nil, err
@@ -210,6 +247,20 @@ func (c *Circuit) SetMaxCurrent(current float64) {
 	c.maxCurrent = current
 }
 
+// GetMaxImbalance returns the max phase imbalance setting
+func (c *Circuit) GetMaxImbalance() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxImbalance
+}
+
+// SetMaxImbalance sets the max phase imbalance
+func (c *Circuit) SetMaxImbalance(current float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxImbalance = current
+}
+
 // RegisterChild registers child circuit
 func (c *Circuit) RegisterChild(child api.Circuit) {
 	c.children = append(c.children, child)
@@ -218,6 +269,7 @@ func (c *Circuit) RegisterChild(child api.Circuit) {
 func (c *Circuit) updateLoadpoints(loadpoints []api.CircuitLoad) {
 	c.power = 0
 	c.current = 0
+	c.currents = [3]float64{}
 
 	for _, lp := range loadpoints {
 		if lp.GetCircuit() != c {
@@ -225,7 +277,23 @@ func (c *Circuit) updateLoadpoints(loadpoints []api.CircuitLoad) {
 		}
 
 		c.power += lp.GetChargePower()
-		c.current += lp.GetMaxPhaseCurrent()
+		c.addPhaseCurrents(lp)
+	}
+}
+
+// addPhaseCurrents adds the load's phase currents or its max phase current on all phases if the phases are unknown
+func (c *Circuit) addPhaseCurrents(load api.CircuitMeasurements) {
+	if pc, ok := load.(api.PhaseCurrents); ok {
+		if i1, i2, i3, err := pc.Currents(); err == nil {
+			c.currents[0] += i1
+			c.currents[1] += i2
+			c.currents[2] += i3
+			return
+		}
+	}
+
+	for i := range c.currents {
+		c.currents[i] += load.GetMaxPhaseCurrent()
 	}
 }
 
@@ -263,28 +331,55 @@ func (c *Circuit) updateMeters() error {
 			}
 		}
 
-		c.current = max(util.SignFromPower(i1, p1), util.SignFromPower(i2, p2), util.SignFromPower(i3, p3))
+		c.currents = [3]float64{util.SignFromPower(i1, p1), util.SignFromPower(i2, p2), util.SignFromPower(i3, p3)}
+		c.current = max(c.currents[0], c.currents[1], c.currents[2])
 		c.currentUpdated = time.Now()
 	}
 
 	return nil
 }
 
+// updateOverload logs power and current and updates the overload state
+func (c *Circuit) updateOverload(maxPower, maxCurrent float64) {
+	var overloaded bool
+
+	if maxPower != 0 && c.power > maxPower {
+		c.log.WARN.Printf("over power detected: %.5gW > %.5gW", c.power, maxPower)
+		overloaded = true
+	} else {
+		c.log.DEBUG.Printf("power: %.5gW", c.power)
+	}
+
+	if maxCurrent != 0 && c.current > maxCurrent {
+		c.log.WARN.Printf("over current detected: %.3gA > %.3gA", c.current, maxCurrent)
+		overloaded = true
+	} else {
+		c.log.DEBUG.Printf("current: %.3gA", c.current)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if overloaded && !c.overloaded {
+		c.overloads++
+		if c.shedding {
+			c.log.WARN.Printf("overload #%d: shedding load", c.overloads)
+		}
+	}
+
+	c.overloaded = overloaded
+	c.relieved = false
+}
+
 func (c *Circuit) Update(loadpoints []api.CircuitLoad) (err error) {
 	maxPower := c.GetMaxPower()
 	maxCurrent := c.GetMaxCurrent()
 
 	defer func() {
-		if maxPower != 0 && c.power > maxPower {
-			c.log.WARN.Printf("over power detected: %.5gW > %.5gW", c.power, maxPower)
-		} else {
-			c.log.DEBUG.Printf("power: %.5gW", c.power)
-		}
+		c.updateOverload(maxPower, maxCurrent)
 
-		if maxCurrent != 0 && c.current > maxCurrent {
-			c.log.WARN.Printf("over current detected: %.3gA > %.3gA", c.current, maxCurrent)
-		} else {
-			c.log.DEBUG.Printf("current: %.3gA", c.current)
+		if maxImbalance := c.GetMaxImbalance(); maxImbalance != 0 && c.imbalance() > maxImbalance {
+			c.log.WARN.Printf("phase imbalance detected: %.3gA > %.3gA (%.3gA/%.3gA/%.3gA)", c.imbalance(), maxImbalance, c.currents[0], c.currents[1], c.currents[2])
 		}
 	}()
 
@@ -297,17 +392,136 @@ func (c *Circuit) Update(loadpoints []api.CircuitLoad) (err error) {
 
 	// meter available
 	if c.meter != nil {
-		return c.updateMeters()
+		if err := c.updateMeters(); err != nil {
+			return err
+		}
+	} else {
+		// no meter available
+		c.updateLoadpoints(loadpoints)
+		for _, ch := range c.children {
+			c.power += ch.GetChargePower()
+			c.addPhaseCurrents(ch)
+		}
+
+		// loads with known phases only count towards their phases
+		c.current = max(c.currents[0], c.currents[1], c.currents[2])
+	}
+
+	return nil
+}
+
+// PollInterval returns the meter polling interval for fast overload detection or zero if disabled
+func (c *Circuit) PollInterval() time.Duration {
+	return c.pollInterval
+}
+
+// Poll updates the circuit's meter measurements and overload state in between regular updates
+func (c *Circuit) Poll() error {
+	if c.meter == nil {
+		return nil
+	}
+
+	err := c.updateMeters()
+	c.updateOverload(c.GetMaxPower(), c.GetMaxCurrent())
+
+	return err
+}
+
+// Shedding returns true if the circuit is overloaded and actively reduces its loads
+func (c *Circuit) Shedding() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.shedding && c.overloaded && !c.relieved
+}
+
+// Shed accounts for a load on the given phases (1..3) reduced by shedding until the next measurement,
+// so that further loads are only reduced by the remaining overload. If the phases are unknown, all phases are reduced.
+func (c *Circuit) Shed(phases []int, current, power float64) {
+	maxPower := c.GetMaxPower()
+	maxCurrent := c.GetMaxCurrent()
+
+	c.mu.Lock()
+
+	for i := range c.currents {
+		if len(phases) == 0 || slices.Contains(phases, i+1) {
+			c.currents[i] = max(0, c.currents[i]-current)
+		}
+	}
+
+	c.current = max(c.currents[0], c.currents[1], c.currents[2])
+	c.power = max(0, c.power-power)
+
+	c.relieved = !(maxPower != 0 && c.power > maxPower || maxCurrent != 0 && c.current > maxCurrent)
+
+	c.mu.Unlock()
+
+	if c.parent != nil {
+		c.parent.Shed(phases, current, power)
 	}
+}
+
+// GetOverloads returns the number of detected overloads
+func (c *Circuit) GetOverloads() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.overloads
+}
 
-	// no meter available
-	c.updateLoadpoints(loadpoints)
+// Distribute allocates the circuits' available current among their consumers according to the distribution policy.
+// Only loadpoints directly attached to the circuit are part of the distribution, the current of child circuits
+// counts as used.
+func (c *Circuit) Distribute(loadpoints []api.CircuitLoad) {
 	for _, ch := range c.children {
-		c.power += ch.GetChargePower()
-		c.current += ch.GetMaxPhaseCurrent()
+		ch.Distribute(loadpoints)
 	}
 
-	return nil
+	maxCurrent := c.GetMaxCurrent()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.allocations = nil
+	if c.policy == nil || maxCurrent == 0 {
+		return
+	}
+
+	var consumers []Consumer
+	var demands []Demand
+
+	// current not used by consumers
+	available := maxCurrent - c.current
+
+	for _, lp := range loadpoints {
+		cons, ok := lp.(Consumer)
+		if !ok || lp.GetCircuit() != c {
+			continue
+		}
+
+		available += lp.GetMaxPhaseCurrent()
+
+		// loadpoints starting to charge are only limited by the circuit's headroom until their demand is known
+		if demand := cons.GetCircuitDemand(); demand.Current > 0 {
+			consumers = append(consumers, cons)
+			demands = append(demands, demand)
+		}
+	}
+
+	res := c.policy.Distribute(max(0, available), demands)
+
+	c.allocations = make(map[api.CircuitLoad]float64, len(consumers))
+	for i, cons := range consumers {
+		c.allocations[cons] = res[i]
+		c.log.TRACE.Printf("allocation: %.3gA of %.3gA requested", res[i], demands[i].Current)
+	}
+}
+
+// GetAllocation returns the load's share of the circuit's current if a distribution policy is configured
+func (c *Circuit) GetAllocation(load api.CircuitLoad) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res, ok := c.allocations[load]
+	return res, ok
 }
 
 // GetChargePower returns the actual power
@@ -320,6 +534,16 @@ func (c *Circuit) GetMaxPhaseCurrent() float64 {
 	return c.current
 }
 
+// Currents implements the api.PhaseCurrents interface
+func (c *Circuit) Currents() (float64, float64, float64, error) {
+	return c.currents[0], c.currents[1], c.currents[2], nil
+}
+
+// imbalance returns the current difference between highest and lowest phase
+func (c *Circuit) imbalance() float64 {
+	return max(c.currents[0], c.currents[1], c.currents[2]) - min(c.currents[0], c.currents[1], c.currents[2])
+}
+
 // ValidatePower validates power request
 func (c *Circuit) ValidatePower(old, new float64) float64 {
 	if maxPower := c.GetMaxPower(); maxPower != 0 {
@@ -344,16 +568,69 @@ func (c *Circuit) ValidatePower(old, new float64) float64 {
 
 // ValidateCurrent validates current request
 func (c *Circuit) ValidateCurrent(old, new float64) float64 {
+	return c.ValidatePhaseCurrent(nil, old, new)
+}
+
+// ValidatePhaseCurrent validates current request of a load connected to the given phases (1..3)
+// against the headroom of these phases. If the phases are unknown, the highest phase current is used.
+func (c *Circuit) ValidatePhaseCurrent(phases []int, old, new float64) float64 {
 	if maxCurrent := c.GetMaxCurrent(); maxCurrent != 0 {
+		current := c.phaseCurrent(phases)
+		delta := max(0, new-old)
+		potential := maxCurrent - current
+
+		if delta > potential {
+			capped := min(new, max(0, old+potential))
+			c.log.DEBUG.Printf("validate current: %.3gA + (%.3gA -> %.3gA) > %.3gA capped at %.3gA", current, old, new, maxCurrent, capped)
+			new = capped
+		} else {
+			c.log.TRACE.Printf("validate current: %.3gA + (%.3gA -> %.3gA) <= %.3gA ok", current, old, new, maxCurrent)
+		}
+	}
+
+	if c.parent == nil {
+		return new
+	}
+
+	return c.parent.ValidatePhaseCurrent(phases, old, new)
+}
+
+// phaseCurrent returns the highest current of the given phases or the max phase current if the phases are unknown
+func (c *Circuit) phaseCurrent(phases []int) float64 {
+	if len(phases) == 0 {
+		return c.current
+	}
+
+	res := math.Inf(-1)
+	for _, p := range phases {
+		res = max(res, c.currents[p-1])
+	}
+
+	return res
+}
+
+// ValidateImbalance validates current request of a load connected to the given phases (1..3)
+func (c *Circuit) ValidateImbalance(phases []int, old, new float64) float64 {
+	if maxImbalance := c.GetMaxImbalance(); maxImbalance != 0 && len(phases) > 0 && len(phases) < 3 {
+		// highest used phase and lowest unused phase
+		hi, lo := math.Inf(-1), math.Inf(1)
+		for i, current := range c.currents {
+			if slices.Contains(phases, i+1) {
+				hi = max(hi, current)
+			} else {
+				lo = min(lo, current)
+			}
+		}
+
 		delta := max(0, new-old)
-		potential := maxCurrent - c.current
+		potential := maxImbalance - (hi - lo)
 
 		if delta > potential {
 			capped := min(new, max(0, old+potential))
-			c.log.DEBUG.Printf("validate current: %.3gA + (%.3gA -> %.3gA) > %.3gA capped at %.3gA", c.current, old, new, maxCurrent, capped)
+			c.log.DEBUG.Printf("validate imbalance: %.3gA - %.3gA + (%.3gA -> %.3gA) > %.3gA capped at %.3gA", hi, lo, old, new, maxImbalance, capped)
 			new = capped
 		} else {
-			c.log.TRACE.Printf("validate current: %.3gA + (%.3gA -> %.3gA) <= %.3gA ok", c.current, old, new, maxCurrent)
+			c.log.TRACE.Printf("validate imbalance: %.3gA - %.3gA + (%.3gA -> %.3gA) <= %.3gA ok", hi, lo, old, new, maxImbalance)
 		}
 	}
 
@@ -361,5 +638,5 @@ func (c *Circuit) ValidateCurrent(old, new float64) float64 {
 		return new
 	}
 
-	return c.parent.ValidateCurrent(old, new)
+	return c.parent.ValidateImbalance(phases, old, new)
 }
@@ -139,3 +139,153 @@ func TestCircuitCurrents(t *testing.T) {
 		ctrl.Finish()
 	}
 }
+
+func TestCircuitImbalance(t *testing.T) {
+	log := util.NewLogger("foo")
+	ctrl := gomock.NewController(t)
+
+	m := struct {
+		*api.MockMeter
+		*api.MockPhaseCurrents
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockPhaseCurrents(ctrl),
+	}
+
+	c, err := New(log, "foo", 0, 0, m, 0)
+	require.NoError(t, err)
+	c.SetMaxImbalance(20)
+
+	m.MockMeter.EXPECT().CurrentPower().AnyTimes().Return(0.0, nil)
+	m.MockPhaseCurrents.EXPECT().Currents().Return(16.0, 2.0, 0.0, nil)
+	require.NoError(t, c.Update(nil))
+
+	for _, tc := range []struct {
+		phases        []int
+		old, new, res float64
+	}{
+		{[]int{1}, 0, 16, 4},   // L1 is already loaded
+		{[]int{1}, 10, 16, 14}, // own load is included in measured current
+		{[]int{2}, 0, 16, 16},  // L2 has headroom
+		{[]int{3}, 0, 32, 22},  // L3 may exceed the least loaded other phase by max imbalance
+		{[]int{1, 2}, 0, 16, 4},
+		{[]int{1, 2, 3}, 0, 32, 32}, // three phase loads don't affect imbalance
+		{nil, 0, 32, 32},            // unknown phases
+	} {
+		assert.Equal(t, tc.res, c.ValidateImbalance(tc.phases, tc.old, tc.new), tc)
+	}
+}
+
+func TestCircuitPhaseCurrents(t *testing.T) {
+	log := util.NewLogger("foo")
+	ctrl := gomock.NewController(t)
+
+	m := struct {
+		*api.MockMeter
+		*api.MockPhaseCurrents
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockPhaseCurrents(ctrl),
+	}
+
+	c, err := New(log, "foo", 32, 0, m, 0)
+	require.NoError(t, err)
+
+	m.MockMeter.EXPECT().CurrentPower().AnyTimes().Return(0.0, nil)
+	m.MockPhaseCurrents.EXPECT().Currents().Return(30.0, 5.0, 0.0, nil)
+	require.NoError(t, c.Update(nil))
+
+	// worst phase without known phases
+	assert.Equal(t, 2.0, c.ValidateCurrent(0, 16))
+	assert.Equal(t, 2.0, c.ValidatePhaseCurrent([]int{1}, 0, 16))
+	assert.Equal(t, 16.0, c.ValidatePhaseCurrent([]int{2}, 0, 16))
+	assert.Equal(t, 27.0, c.ValidatePhaseCurrent([]int{2, 3}, 0, 32))
+}
+
+type phaseLoad struct {
+	circuit    api.Circuit
+	i1, i2, i3 float64
+}
+
+func (l phaseLoad) GetCircuit() api.Circuit {
+	return l.circuit
+}
+
+func (l phaseLoad) GetChargePower() float64 {
+	return 230 * (l.i1 + l.i2 + l.i3)
+}
+
+func (l phaseLoad) GetMaxPhaseCurrent() float64 {
+	return max(l.i1, l.i2, l.i3)
+}
+
+func (l phaseLoad) Currents() (float64, float64, float64, error) {
+	return l.i1, l.i2, l.i3, nil
+}
+
+func TestCircuitLoadpointPhaseCurrents(t *testing.T) {
+	log := util.NewLogger("foo")
+
+	c, err := New(log, "foo", 32, 0, nil, 0)
+	require.NoError(t, err)
+
+	// two single phase loads on L1 and L2
+	require.NoError(t, c.Update([]api.CircuitLoad{
+		phaseLoad{circuit: c, i1: 16},
+		phaseLoad{circuit: c, i2: 16},
+	}))
+
+	assert.Equal(t, 16.0, c.GetMaxPhaseCurrent())
+	assert.Equal(t, 16.0, c.ValidatePhaseCurrent([]int{1}, 0, 32))
+	assert.Equal(t, 32.0, c.ValidatePhaseCurrent([]int{3}, 0, 32))
+}
+
+func TestCircuitOverload(t *testing.T) {
+	log := util.NewLogger("foo")
+	ctrl := gomock.NewController(t)
+
+	m := struct {
+		*api.MockMeter
+		*api.MockPhaseCurrents
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockPhaseCurrents(ctrl),
+	}
+
+	c, err := New(log, "foo", 32, 0, m, 0)
+	require.NoError(t, err)
+
+	m.MockMeter.EXPECT().CurrentPower().AnyTimes().Return(0.0, nil)
+
+	// overload is counted but not shed without shedding mode
+	m.MockPhaseCurrents.EXPECT().Currents().Return(34.0, 0.0, 0.0, nil)
+	require.NoError(t, c.Update(nil))
+	assert.Equal(t, 1, c.GetOverloads())
+	assert.False(t, c.Shedding())
+
+	c.shedding = true
+
+	// continued overload is counted once
+	m.MockPhaseCurrents.EXPECT().Currents().Return(34.0, 0.0, 0.0, nil)
+	require.NoError(t, c.Poll())
+	assert.Equal(t, 1, c.GetOverloads())
+	assert.True(t, c.Shedding())
+
+	// existing load is reduced by the excess current
+	assert.Equal(t, 14.0, c.ValidatePhaseCurrent([]int{1}, 16, 16))
+
+	// shed load is accounted for until the next measurement
+	c.Shed([]int{1}, 2, 460)
+	assert.False(t, c.Shedding())
+	assert.Equal(t, 16.0, c.ValidatePhaseCurrent([]int{1}, 16, 16))
+
+	m.MockPhaseCurrents.EXPECT().Currents().Return(30.0, 0.0, 0.0, nil)
+	require.NoError(t, c.Poll())
+	assert.False(t, c.Shedding())
+
+	// new overload
+	m.MockPhaseCurrents.EXPECT().Currents().Return(33.0, 0.0, 0.0, nil)
+	require.NoError(t, c.Update(nil))
+	assert.Equal(t, 2, c.GetOverloads())
+	assert.True(t, c.Shedding())
+}
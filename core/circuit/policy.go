@@ -0,0 +1,174 @@
+package circuit
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Demand is a load's request for circuit capacity
+type Demand struct {
+	Current    float64   // A requested phase current, 0 = no demand
+	MinCurrent float64   // A min phase current required to charge at all
+	Priority   int       // higher priority is served first
+	PlanTime   time.Time // charging plan target time, zero if none
+	Soc        float64   // % vehicle soc, 0 if unknown
+}
+
+// Consumer is a circuit load that participates in the circuit's capacity distribution
+type Consumer interface {
+	api.CircuitLoad
+	GetCircuitDemand() Demand
+}
+
+// Policy distributes the available phase current among the demands.
+// The result is index-aligned with the demands. Allocations are either 0 or at least the demand's min current.
+type Policy interface {
+	Distribute(available float64, demands []Demand) []float64
+}
+
+// NewPolicy creates a distribution policy by name
+func NewPolicy(name string) (Policy, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return nil, nil
+	case "equal":
+		return weighted(func(Demand) float64 { return 1 }), nil
+	case "priority":
+		return weighted(func(d Demand) float64 { return float64(max(d.Priority, 0) + 1) }), nil
+	case "deadline":
+		return sequential(deadlineFirst), nil
+	case "soc":
+		return sequential(socLowestFirst), nil
+	default:
+		return nil, fmt.Errorf("invalid policy: %s", name)
+	}
+}
+
+// sequential serves demands one after another in the policy's order
+type sequential func(a, b Demand) int
+
+func (p sequential) Distribute(available float64, demands []Demand) []float64 {
+	res := make([]float64, len(demands))
+
+	order := make([]int, len(demands))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(i, j int) int {
+		return p(demands[i], demands[j])
+	})
+
+	for _, i := range order {
+		if current := min(demands[i].Current, available); current > 0 && current >= demands[i].MinCurrent {
+			res[i] = current
+			available -= current
+		}
+	}
+
+	return res
+}
+
+// deadlineFirst orders demands by earliest plan time, demands without plan last
+func deadlineFirst(a, b Demand) int {
+	switch {
+	case a.PlanTime.IsZero() && b.PlanTime.IsZero():
+		return cmp.Compare(b.Priority, a.Priority)
+	case a.PlanTime.IsZero():
+		return +1
+	case b.PlanTime.IsZero():
+		return -1
+	default:
+		return a.PlanTime.Compare(b.PlanTime)
+	}
+}
+
+// socLowestFirst orders demands by lowest soc, unknown soc last
+func socLowestFirst(a, b Demand) int {
+	switch {
+	case a.Soc == 0 && b.Soc == 0:
+		return cmp.Compare(b.Priority, a.Priority)
+	case a.Soc == 0:
+		return +1
+	case b.Soc == 0:
+		return -1
+	default:
+		return cmp.Compare(a.Soc, b.Soc)
+	}
+}
+
+// weighted shares the available current in proportion to the demands' weights
+type weighted func(d Demand) float64
+
+func (p weighted) Distribute(available float64, demands []Demand) []float64 {
+	res := make([]float64, len(demands))
+
+	var active []int
+	for i, d := range demands {
+		if d.Current > 0 {
+			active = append(active, i)
+		}
+	}
+
+	// lowest weight is dropped first if min current can't be served
+	slices.SortStableFunc(active, func(i, j int) int {
+		return cmp.Compare(p(demands[j]), p(demands[i]))
+	})
+
+	for len(active) > 0 {
+		remaining := available
+		share := make(map[int]float64, len(active))
+
+		// water-filling: demands below their share are served completely, the rest is shared again
+		open := slices.Clone(active)
+		for len(open) > 0 {
+			var total float64
+			for _, i := range open {
+				total += p(demands[i])
+			}
+
+			var satisfied bool
+			for k, i := range open {
+				if s := remaining * p(demands[i]) / total; demands[i].Current <= s {
+					share[i] = demands[i].Current
+					remaining -= demands[i].Current
+					open = slices.Delete(open, k, k+1)
+					satisfied = true
+					break
+				}
+			}
+
+			if !satisfied {
+				for _, i := range open {
+					share[i] = remaining * p(demands[i]) / total
+				}
+				break
+			}
+		}
+
+		// drop the lowest weighted demand that can't be served with its min current
+		dropped := -1
+		for k := len(active) - 1; k >= 0; k-- {
+			if i := active[k]; share[i] < demands[i].MinCurrent {
+				dropped = k
+				break
+			}
+		}
+
+		if dropped < 0 {
+			for i, s := range share {
+				res[i] = s
+			}
+			break
+		}
+
+		active = slices.Delete(active, dropped, dropped+1)
+	}
+
+	return res
+}
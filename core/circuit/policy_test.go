@@ -0,0 +1,121 @@
+package circuit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyEqual(t *testing.T) {
+	p, err := NewPolicy("equal")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		available float64
+		demands   []Demand
+		res       []float64
+	}{
+		// two cars share the circuit
+		{32, []Demand{{Current: 32, MinCurrent: 6}, {Current: 32, MinCurrent: 6}}, []float64{16, 16}},
+		// smaller demand is served completely, remainder goes to the other
+		{32, []Demand{{Current: 10, MinCurrent: 6}, {Current: 32, MinCurrent: 6}}, []float64{10, 22}},
+		// no demand
+		{32, []Demand{{}, {Current: 16, MinCurrent: 6}}, []float64{0, 16}},
+		// not enough for both min currents
+		{10, []Demand{{Current: 16, MinCurrent: 6}, {Current: 16, MinCurrent: 6}}, []float64{10, 0}},
+	} {
+		assert.Equal(t, tc.res, p.Distribute(tc.available, tc.demands), tc)
+	}
+}
+
+func TestPolicyPriority(t *testing.T) {
+	p, err := NewPolicy("priority")
+	require.NoError(t, err)
+
+	// weighted by priority
+	assert.Equal(t, []float64{8, 24}, p.Distribute(32, []Demand{
+		{Current: 32, MinCurrent: 6},
+		{Current: 32, MinCurrent: 6, Priority: 2},
+	}))
+
+	// lowest priority is dropped first
+	assert.Equal(t, []float64{0, 10}, p.Distribute(10, []Demand{
+		{Current: 16, MinCurrent: 6},
+		{Current: 16, MinCurrent: 6, Priority: 1},
+	}))
+}
+
+func TestPolicyDeadline(t *testing.T) {
+	p, err := NewPolicy("deadline")
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	assert.Equal(t, []float64{0, 16, 16}, p.Distribute(32, []Demand{
+		{Current: 16, MinCurrent: 6},
+		{Current: 16, MinCurrent: 6, PlanTime: now.Add(2 * time.Hour)},
+		{Current: 16, MinCurrent: 6, PlanTime: now.Add(time.Hour)},
+	}))
+
+	// remainder below min current is not used
+	assert.Equal(t, []float64{0, 16, 10}, p.Distribute(26, []Demand{
+		{Current: 16, MinCurrent: 6},
+		{Current: 16, MinCurrent: 6, PlanTime: now.Add(time.Hour)},
+		{Current: 16, MinCurrent: 6, PlanTime: now.Add(2 * time.Hour)},
+	}))
+}
+
+func TestPolicySoc(t *testing.T) {
+	p, err := NewPolicy("soc")
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{16, 0, 16}, p.Distribute(32, []Demand{
+		{Current: 16, MinCurrent: 6, Soc: 20},
+		{Current: 16, MinCurrent: 6},
+		{Current: 16, MinCurrent: 6, Soc: 50},
+	}))
+}
+
+func TestPolicyInvalid(t *testing.T) {
+	p, err := NewPolicy("")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = NewPolicy("foo")
+	assert.Error(t, err)
+}
+
+type consumer struct {
+	phaseLoad
+	demand Demand
+}
+
+func (c *consumer) GetCircuitDemand() Demand {
+	return c.demand
+}
+
+func TestCircuitAllocation(t *testing.T) {
+	c, err := New(util.NewLogger("foo"), "foo", 32, 0, nil, 0)
+	require.NoError(t, err)
+
+	c.policy, err = NewPolicy("equal")
+	require.NoError(t, err)
+
+	// first car is charging at full current, second one connects
+	lp1 := &consumer{phaseLoad{circuit: c, i1: 32, i2: 32, i3: 32}, Demand{Current: 32, MinCurrent: 6}}
+	lp2 := &consumer{phaseLoad{circuit: c}, Demand{Current: 32, MinCurrent: 6}}
+	require.NoError(t, c.Update([]api.CircuitLoad{lp1, lp2}))
+	c.Distribute([]api.CircuitLoad{lp1, lp2})
+
+	res, ok := c.GetAllocation(lp1)
+	assert.True(t, ok)
+	assert.Equal(t, 16.0, res)
+
+	res, ok = c.GetAllocation(lp2)
+	assert.True(t, ok)
+	assert.Equal(t, 16.0, res)
+}
@@ -0,0 +1,191 @@
+package forecast
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/jinzhu/now"
+)
+
+const (
+	DefaultDecay = 0.1 // weight of a new hourly average once a slot has enough history
+
+	minCoverage = 30 * time.Minute // min measured duration for an hourly average
+	maxGap      = 5 * time.Minute  // max gap between measurements that is interpolated
+	horizon     = 48 * time.Hour   // forecast horizon
+)
+
+// Slot is the average power of one hour of a weekday
+type Slot struct {
+	Power float64 `json:"power"` // W
+	Count int     `json:"count"` // number of averaged hours
+}
+
+// Profile is the household consumption by weekday and hour of day
+type Profile [7][24]Slot
+
+// Home learns the household consumption profile from measured home power
+type Home struct {
+	mu      sync.RWMutex
+	clock   clock.Clock
+	decay   float64
+	profile Profile
+
+	// current hour
+	hour     time.Time
+	updated  time.Time
+	power    float64       // W last measured power
+	energy   float64       // Wh accumulated
+	duration time.Duration // measured duration
+}
+
+// NewHome creates a household consumption forecast
+func NewHome(opt ...func(h *Home)) *Home {
+	h := &Home{
+		clock: clock.New(),
+		decay: DefaultDecay,
+	}
+
+	for _, o := range opt {
+		o(h)
+	}
+
+	return h
+}
+
+// WithDecay sets the weight of new hourly averages
+func WithDecay(decay float64) func(h *Home) {
+	return func(h *Home) {
+		h.decay = decay
+	}
+}
+
+// Update adds a home power measurement and returns true if the profile was updated
+func (h *Home) Update(power float64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ts := h.clock.Now()
+	hour := now.With(ts).BeginningOfHour()
+
+	defer func() {
+		h.updated, h.power = ts, power
+	}()
+
+	// start over after missing measurements
+	if h.updated.IsZero() || ts.Sub(h.updated) > maxGap {
+		h.hour, h.energy, h.duration = hour, 0, 0
+		return false
+	}
+
+	var updated bool
+
+	// close completed hours
+	from := h.updated
+	for hour.After(h.hour) {
+		end := h.hour.Add(time.Hour)
+		h.add(end.Sub(from))
+		updated = h.fold() || updated
+
+		from = end
+		h.hour, h.energy, h.duration = end, 0, 0
+	}
+
+	h.add(ts.Sub(from))
+
+	return updated
+}
+
+// add accumulates the last measured power for given duration
+func (h *Home) add(d time.Duration) {
+	h.energy += h.power * d.Hours()
+	h.duration += d
+}
+
+// fold adds the current hour's average to the profile
+func (h *Home) fold() bool {
+	if h.duration < minCoverage {
+		return false
+	}
+
+	slot := &h.profile[h.hour.Weekday()][h.hour.Hour()]
+
+	// plain average until enough history, exponential decay afterwards
+	alpha := max(h.decay, 1/float64(slot.Count+1))
+	slot.Power += alpha * (h.energy/h.duration.Hours() - slot.Power)
+	slot.Count++
+
+	return true
+}
+
+// Rates returns the expected household consumption in W as hourly rates
+func (h *Home) Rates() (api.Rates, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	start := now.With(h.clock.Now()).BeginningOfHour()
+
+	res := make(api.Rates, 0, horizon/time.Hour)
+	for ts := start; ts.Before(start.Add(horizon)); ts = ts.Add(time.Hour) {
+		power, ok := h.at(ts)
+		if !ok {
+			return nil, errors.New("no home profile")
+		}
+
+		res = append(res, api.Rate{Start: ts, End: ts.Add(time.Hour), Value: power})
+	}
+
+	return res, nil
+}
+
+// at returns the expected power for given time, falling back to the same hour of other weekdays or the overall average
+func (h *Home) at(ts time.Time) (float64, bool) {
+	if slot := h.profile[ts.Weekday()][ts.Hour()]; slot.Count > 0 {
+		return slot.Power, true
+	}
+
+	var hour, total float64
+	var hourCount, totalCount int
+
+	for day := range h.profile {
+		for hr, slot := range h.profile[day] {
+			if slot.Count == 0 {
+				continue
+			}
+
+			total += slot.Power
+			totalCount++
+
+			if hr == ts.Hour() {
+				hour += slot.Power
+				hourCount++
+			}
+		}
+	}
+
+	switch {
+	case hourCount > 0:
+		return hour / float64(hourCount), true
+	case totalCount > 0:
+		return total / float64(totalCount), true
+	default:
+		return 0, false
+	}
+}
+
+// Profile returns the learned profile for persistence
+func (h *Home) Profile() Profile {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.profile
+}
+
+// Restore restores a persisted profile
+func (h *Home) Restore(profile Profile) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.profile = profile
+}
@@ -0,0 +1,78 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run measures constant power for given duration
+func run(h *Home, clock *clock.Mock, power float64, d time.Duration) bool {
+	updated := h.Update(power)
+	for end := clock.Now().Add(d); clock.Now().Before(end); {
+		clock.Add(time.Minute)
+		updated = h.Update(power) || updated
+	}
+	return updated
+}
+
+func TestHomeProfile(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)) // monday
+	h := NewHome(func(h *Home) { h.clock = clock })
+
+	_, err := h.Rates()
+	assert.Error(t, err)
+
+	assert.True(t, run(h, clock, 500, time.Hour))
+	assert.InDelta(t, 500, h.Profile()[time.Monday][0].Power, 1e-6)
+
+	assert.True(t, run(h, clock, 1000, time.Hour))
+	assert.InDelta(t, 1000, h.Profile()[time.Monday][1].Power, 1e-6)
+	assert.Equal(t, 1, h.Profile()[time.Monday][1].Count)
+
+	rates, err := h.Rates()
+	require.NoError(t, err)
+	assert.Len(t, rates, 48)
+
+	// unknown slots fall back to the same hour of other weekdays, then to the average
+	assert.InDelta(t, 750.0, rates[0].Value, 1e-6, "monday 2:00")
+	assert.InDelta(t, 500.0, rates[22].Value, 1e-6, "tuesday 0:00")
+	assert.InDelta(t, 1000.0, rates[23].Value, 1e-6, "tuesday 1:00")
+}
+
+func TestHomeDecay(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	h := NewHome(func(h *Home) { h.clock = clock })
+
+	for range 10 {
+		run(h, clock, 1000, time.Hour)
+
+		// continue a week later
+		clock.Add(7*24*time.Hour - time.Hour)
+	}
+
+	slot := h.Profile()[time.Monday][0]
+	assert.Equal(t, 10, slot.Count)
+	assert.InDelta(t, 1000, slot.Power, 1e-6)
+
+	// new consumption pattern is learned with exponential decay
+	run(h, clock, 2000, time.Hour)
+	assert.InDelta(t, 1100, h.Profile()[time.Monday][0].Power, 1e-6)
+}
+
+func TestHomeGap(t *testing.T) {
+	clock := clock.NewMock()
+	h := NewHome(func(h *Home) { h.clock = clock })
+
+	h.Update(1000)
+	clock.Add(40 * time.Minute)
+
+	// measurements before the gap are discarded, remaining coverage is insufficient
+	assert.False(t, run(h, clock, 1000, 30*time.Minute))
+	assert.Equal(t, Profile{}, h.Profile())
+}
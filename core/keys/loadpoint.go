@@ -83,6 +83,8 @@ const (
 
 	// repeating plans
 	RepeatingPlans = "repeatingPlans" // key to access all repeating plans in db
+	DepartureSoc   = "departureSoc"   // soc for repeating plans created from learned departures
+	DeparturePlans = "departurePlans" // repeating plans created from learned departures
 
 	// remote control
 	RemoteDisabled       = "remoteDisabled"       // remote disabled
@@ -104,8 +106,16 @@ const (
 	SpeedEstimatorEnabled       = "speedEstimatorEnabled"       // speed estimator enabled
 	SpeedEstimatorActive        = "speedEstimatorActive"        // speed estimator currently active
 	SpeedEstimatorEstimatedSoc  = "speedEstimatorEstimatedSoc"  // speed estimator estimated SoC
-	SpeedEstimatorTargetSoc     = "speedEstimatorTargetSoc"     // speed estimator target SoC
+	SpeedEstimatorTargetSoc     = "speedEstimatorTargetSoc"     // speed estimator target SoC (also vehicle setting)
 	SpeedEstimatorTargetReached = "speedEstimatorTargetReached" // speed estimator target reached
 	SpeedEstimatorMaxPower      = "speedEstimatorMaxPower"      // speed estimator max observed power
 	SpeedEstimatorMeasurements  = "speedEstimatorMeasurements"  // speed estimator measurement count
+	SpeedEstimatorCurveSessions = "speedEstimatorCurveSessions" // speed estimator sessions contributing to learned curve
+	SpeedEstimatorFinished      = "speedEstimatorFinished"      // vehicle stopped charging on its own
+	SpeedEstimatorFull          = "speedEstimatorFull"          // vehicle stopped charging with full battery
+	SpeedEstimatorCurve         = "speedEstimatorCurve"         // speed estimator learned charging curve (vehicle setting)
+	SpeedEstimatorState         = "speedEstimatorState"         // speed estimator session snapshot (loadpoint setting)
+
+	SpeedEstimatorReductionThreshold = "speedEstimatorReductionThreshold" // speed estimator power reduction threshold (vehicle setting)
+	SpeedEstimatorMinPower           = "speedEstimatorMinPower"           // speed estimator min power for estimation (vehicle setting)
 )
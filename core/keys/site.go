@@ -11,11 +11,17 @@ const (
 	GridConfigured        = "gridConfigured"
 	Grid                  = "grid"
 	HomePower             = "homePower"
+	HomeProfile           = "homeProfile"
+	MaxGridExport         = "maxGridExport"
+	PeakShaving           = "peakShaving"
 	PrioritySoc           = "prioritySoc"
 	Pv                    = "pv"
+	PvCurtailedEnergy     = "pvCurtailedEnergy"
+	PvCurtailedPower      = "pvCurtailedPower"
 	PvEnergy              = "pvEnergy"
 	PvPower               = "pvPower"
 	ResidualPower         = "residualPower"
+	Schedules             = "schedules"
 	SiteTitle             = "siteTitle"
 	SmartCostType         = "smartCostType"
 	Statistics            = "statistics"
@@ -43,6 +49,7 @@ const (
 	BatteryCapacity         = "batteryCapacity"
 	BatteryDischargeControl = "batteryDischargeControl"
 	BatteryGridChargeLimit  = "batteryGridChargeLimit"
+	BatteryGridChargePower  = "batteryGridChargePower"
 	BatteryGridChargeActive = "batteryGridChargeActive"
 	BufferSoc               = "bufferSoc"
 	BufferStartSoc          = "bufferStartSoc"
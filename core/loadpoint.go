@@ -57,6 +57,8 @@ const (
 	chargerSwitchDuration = 60 * time.Second // allow out of sync during this timespan
 	phaseSwitchDuration   = 60 * time.Second // allow out of sync and do not measure phases during this timespan
 
+	speedEstimatorSnapshotInterval = 5 * time.Minute // store speed estimator session for resuming after restart
+
 	// battery boost states
 	boostDisabled = 0
 	boostStart    = 1
@@ -88,10 +90,11 @@ type Loadpoint struct {
 	vmu          sync.RWMutex // guard vehicle
 
 	// exposed public configuration
-	CircuitRef string `mapstructure:"circuit"` // Circuit reference
-	ChargerRef string `mapstructure:"charger"` // Charger reference
-	VehicleRef string `mapstructure:"vehicle"` // Vehicle reference
-	MeterRef   string `mapstructure:"meter"`   // Charge meter reference
+	CircuitRef   string `mapstructure:"circuit"`      // Circuit reference
+	CircuitPhase int    `mapstructure:"circuitPhase"` // Circuit phase (1-3) connected to the charger's L1, 0 = unknown
+	ChargerRef   string `mapstructure:"charger"`      // Charger reference
+	VehicleRef   string `mapstructure:"vehicle"`      // Vehicle reference
+	MeterRef     string `mapstructure:"meter"`        // Charge meter reference
 
 	Soc             loadpoint.SocConfig
 	Enable, Disable loadpoint.ThresholdConfig
@@ -123,6 +126,7 @@ type Loadpoint struct {
 	phases              int       // Charger enabled phases, guarded by mutex
 	measuredPhases      int       // Charger physically measured phases
 	offeredCurrent      float64   // Charger current limit
+	requestedCurrent    float64   // Current requested before circuit limits
 	socUpdated          time.Time // Soc updated timestamp (poll: connected)
 	vehicleDetect       time.Time // Vehicle connected timestamp
 	chargerSwitched     time.Time // Charger enabled/disabled timestamp
@@ -144,11 +148,13 @@ type Loadpoint struct {
 
 	// charge planning
 	planner          *planner.Planner
+	sitePlanner      *sitePlanner  // joint planning with other loadpoints
 	planTime         time.Time     // time goal
 	planPrecondition time.Duration // precondition duration
 	planEnergy       float64       // Plan charge energy in kWh (dumb vehicles)
 	planSlotEnd      time.Time     // current plan slot end time
 	planActive       bool          // charge plan exists and has a currently active slot
+	planPower        float64       // planned power of the active slot if below max power
 
 	// cached state
 	status         api.ChargeStatus       // Charger status
@@ -170,10 +176,17 @@ type Loadpoint struct {
 
 	// charging speed-based SoC estimation
 	speedEstimator *soc.SpeedEstimator // Speed-based SoC estimator for vehicles without SoC reporting
+	fusedEstimator *soc.FusedEstimator // Continuous SoC from speed estimator and charged energy
+	fusedSoc       bool                // Vehicle soc is estimated by the fused estimator
+
+	speedEstimatorVehicle  string    // Vehicle name of the speed estimator session, empty for unnamed vehicles
+	speedEstimatorSnapshot time.Time // Speed estimator session last stored
+	speedEstimatorResumed  bool      // Speed estimator session resumed after restart, kept on the following connect
 
 	// session log
-	db      *session.DB
-	session *session.Session
+	db            *session.DB
+	session       *session.Session
+	sessionSample time.Time // last charging curve sample of the session
 
 	settings settings.Settings
 
@@ -224,6 +237,10 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, other
 		lp.circuit = dev.Instance()
 	}
 
+	if lp.CircuitPhase < 0 || lp.CircuitPhase > 3 {
+		return lp, fmt.Errorf("invalid circuit phase: %d", lp.CircuitPhase)
+	}
+
 	if lp.MeterRef != "" {
 		dev, err := config.Meters().ByName(lp.MeterRef)
 		if err != nil {
@@ -470,7 +487,12 @@ func (lp *Loadpoint) evChargeStopHandler() {
 
 	// stop speed estimator if available
 	if lp.speedEstimator != nil {
+		// vehicle stopped charging on its own
+		if lp.enabled {
+			lp.speedEstimator.FinishCharging(lp.chargePower)
+		}
 		lp.speedEstimator.StopCharging()
+		lp.snapshotSpeedEstimator()
 	}
 
 	// reset pv enable/disable timer
@@ -497,6 +519,10 @@ func (lp *Loadpoint) evVehicleConnectHandler() {
 	if lp.socEstimator != nil {
 		lp.socEstimator.Reset()
 	}
+	if lp.fusedEstimator != nil && !lp.speedEstimatorResumed {
+		lp.fusedEstimator.Reset()
+	}
+	lp.speedEstimatorResumed = false
 
 	// set default or start detection
 	if !lp.chargerHasFeature(api.IntegratedDevice) {
@@ -531,6 +557,14 @@ func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	// forget startup energy offset
 	lp.chargedAtStartup = 0
 
+	// learn from the ended session and forget it
+	if lp.speedEstimator != nil {
+		lp.speedEstimator.EndSession()
+		lp.speedEstimator.Reset()
+		lp.settings.SetString(keys.SpeedEstimatorState, "")
+	}
+	lp.speedEstimatorResumed = false
+
 	// remove charger vehicle id and stop potential detection
 	lp.setVehicleIdentifier("")
 	lp.stopVehicleDetection()
@@ -854,8 +888,17 @@ func (lp *Loadpoint) roundedCurrent(current float64) float64 {
 func (lp *Loadpoint) setLimit(current float64) error {
 	current = lp.roundedCurrent(current)
 
+	// current before circuit limits is the loadpoint's circuit demand
+	lp.requestedCurrent = current
+
 	// apply circuit limits
 	if lp.circuit != nil {
+		// share of the circuit according to its distribution policy
+		if allocation, ok := lp.circuit.GetAllocation(lp); ok && allocation < current {
+			lp.log.DEBUG.Printf("circuit allocation: %.3gA", allocation)
+			current = lp.roundedCurrent(allocation)
+		}
+
 		var actualCurrent float64
 		if lp.chargeCurrents != nil {
 			actualCurrent = max(lp.chargeCurrents[0], lp.chargeCurrents[1], lp.chargeCurrents[2])
@@ -863,9 +906,15 @@ func (lp *Loadpoint) setLimit(current float64) error {
 			actualCurrent = lp.offeredCurrent
 		}
 
-		currentLimit := lp.circuit.ValidateCurrent(actualCurrent, current)
-
+		// per-phase headroom if the circuit phases are known
 		activePhases := lp.ActivePhases()
+		phases := lp.circuitPhases(activePhases)
+
+		currentLimit := lp.circuit.ValidatePhaseCurrent(phases, actualCurrent, current)
+		if phases != nil {
+			currentLimit = min(currentLimit, lp.circuit.ValidateImbalance(phases, actualCurrent, current))
+		}
+
 		powerLimit := lp.circuit.ValidatePower(lp.chargePower, currentToPower(current, activePhases))
 		currentLimitViaPower := powerToCurrent(powerLimit, activePhases)
 
@@ -943,6 +992,40 @@ func (lp *Loadpoint) setLimit(current float64) error {
 	return nil
 }
 
+// circuitShedding returns true if the loadpoint's circuit or any of its parents sheds load
+func (lp *Loadpoint) circuitShedding() bool {
+	for c := lp.circuit; c != nil; c = c.GetParent() {
+		if c.Shedding() {
+			return true
+		}
+	}
+	return false
+}
+
+// shed re-applies the charge current limit against the circuit limits to immediately reduce an overloaded circuit.
+// The reduction is accounted for by the circuits, so that following loadpoints only shed the remaining overload.
+func (lp *Loadpoint) shed() {
+	if !lp.enabled || !lp.circuitShedding() {
+		return
+	}
+
+	lp.log.DEBUG.Println("circuit overload: reducing charge current")
+
+	current := lp.offeredCurrent
+	if err := lp.setLimit(current); err != nil {
+		lp.log.ERROR.Println(err)
+	}
+
+	if lp.enabled {
+		current -= lp.offeredCurrent
+	}
+
+	if current > 0 {
+		activePhases := lp.ActivePhases()
+		lp.circuit.Shed(lp.circuitPhases(activePhases), current, currentToPower(current, activePhases))
+	}
+}
+
 // connected returns the EVs connection state
 func (lp *Loadpoint) connected() bool {
 	status := lp.GetStatus()
@@ -961,10 +1044,16 @@ func (lp *Loadpoint) setStatus(status api.ChargeStatus) {
 	lp.status = status
 }
 
-// socBasedPlanning returns true if vehicle soc (optionally from charger) and capacity are available
+// socBasedPlanning returns true if vehicle soc (optionally from charger or estimated) and capacity are available
 func (lp *Loadpoint) socBasedPlanning() bool {
 	v := lp.GetVehicle()
-	return (v != nil && v.Capacity() > 0) && (lp.vehicleHasSoc() || lp.vehicleSoc > 0)
+	return (v != nil && v.Capacity() > 0) && (lp.vehicleHasSoc() || lp.vehicleSoc > 0 && !lp.fusedSoc || lp.fusedSocPlanning())
+}
+
+// fusedSocPlanning returns true if soc goals of a vehicle without soc use the estimated soc.
+// Energy plans and limits take precedence.
+func (lp *Loadpoint) fusedSocPlanning() bool {
+	return lp.fusedSoc && lp.planEnergy == 0 && lp.limitEnergy == 0
 }
 
 // repeatingPlanning returns true if the current plan is a repeating plan
@@ -1227,6 +1316,11 @@ func (lp *Loadpoint) scalePhases(phases int) error {
 
 		// update setting and reset timer
 		lp.SetPhases(phases)
+
+		// power change is not caused by the vehicle
+		if lp.speedEstimator != nil {
+			lp.speedEstimator.ResetReference("phase switch")
+		}
 	}
 
 	return nil
@@ -1241,6 +1335,16 @@ func (lp *Loadpoint) fastCharging() error {
 	return err
 }
 
+// planCharging sets the current of the active plan slot's power or charges at maximum current
+func (lp *Loadpoint) planCharging() error {
+	if lp.planPower <= 0 {
+		return lp.fastCharging()
+	}
+
+	current := powerToCurrent(lp.planPower, lp.ActivePhases())
+	return lp.setLimit(min(max(current, lp.effectiveMinCurrent()), lp.effectiveMaxCurrent()))
+}
+
 // pvScalePhases switches phases if necessary and returns number of phases switched to
 func (lp *Loadpoint) pvScalePhases(sitePower, minCurrent, maxCurrent float64) int {
 	phases := lp.GetPhases()
@@ -1262,6 +1366,12 @@ func (lp *Loadpoint) pvScalePhases(sitePower, minCurrent, maxCurrent float64) in
 	availablePower := lp.chargePower - sitePower
 	scalable := (sitePower > 0 || !lp.enabled) && activePhases > 1 && lp.phasesConfigured < 3
 
+	// don't scale down if single phase charging would exceed the circuit's phase imbalance
+	if scalable && lp.imbalanceLimited(minCurrent) {
+		lp.log.DEBUG.Printf("phase imbalance prevents %s", phaseScale1p)
+		scalable = false
+	}
+
 	// scale down phases
 	if targetCurrent := powerToCurrent(availablePower, activePhases); targetCurrent < minCurrent && scalable {
 		lp.log.DEBUG.Printf("available power %.0fW < %.0fW min %dp threshold", availablePower, float64(activePhases)*Voltage*minCurrent, activePhases)
@@ -1289,7 +1399,7 @@ func (lp *Loadpoint) pvScalePhases(sitePower, minCurrent, maxCurrent float64) in
 
 	maxPhases := lp.MaxActivePhases()
 	target1pCurrent := powerToCurrent(availablePower, 1)
-	scalable = maxPhases > 1 && phases < maxPhases && target1pCurrent > maxCurrent
+	scalable = maxPhases > 1 && phases < maxPhases && (target1pCurrent > maxCurrent || lp.imbalanceLimited(min(target1pCurrent, maxCurrent)))
 
 	// scale up phases
 	if targetCurrent := powerToCurrent(availablePower, maxPhases); targetCurrent >= minCurrent && scalable {
@@ -1517,9 +1627,16 @@ func (lp *Loadpoint) UpdateChargePowerAndCurrents() float64 {
 		lp.log.DEBUG.Printf("charge power: %.0fW", power)
 		lp.publish(keys.ChargePower, power)
 
-		// Update speed estimator with current power
-		if lp.speedEstimator != nil {
-			lp.speedEstimator.UpdatePower(power)
+		if lp.charging() {
+			phases := lp.ActivePhases()
+
+			// Update speed estimator with current power and the power offered by the loadpoint.
+			// Uses the commanded current since effectiveCurrent follows the measured currents and would hide the vehicle's taper.
+			if lp.speedEstimator != nil {
+				lp.speedEstimator.UpdatePower(power, currentToPower(lp.offeredCurrent, phases))
+			}
+
+			lp.recordSessionCurve(power, phases)
 		}
 
 		// https://github.com/evcc-io/evcc/issues/2153
@@ -1644,11 +1761,45 @@ func (lp *Loadpoint) publishSpeedEstimatorStatus() {
 	// Publish status
 	lp.publish(keys.SpeedEstimatorEnabled, status["enabled"])
 	lp.publish(keys.SpeedEstimatorActive, status["estimationActive"])
-	lp.publish(keys.SpeedEstimatorEstimatedSoc, status["estimatedSoc"])
+	// vehicle reached its own limit, there is no meaningful soc estimate
+	if status["vehicleFinished"] == true && status["vehicleFull"] != true {
+		lp.publish(keys.SpeedEstimatorEstimatedSoc, nil)
+	} else {
+		lp.publish(keys.SpeedEstimatorEstimatedSoc, status["estimatedSoc"])
+	}
 	lp.publish(keys.SpeedEstimatorTargetSoc, status["targetSoc"])
 	lp.publish(keys.SpeedEstimatorTargetReached, status["targetReached"])
 	lp.publish(keys.SpeedEstimatorMaxPower, status["maxPower"])
 	lp.publish(keys.SpeedEstimatorMeasurements, status["measurementCount"])
+	lp.publish(keys.SpeedEstimatorCurveSessions, status["curveSessions"])
+	lp.publish(keys.SpeedEstimatorFinished, status["vehicleFinished"])
+	lp.publish(keys.SpeedEstimatorFull, status["vehicleFull"])
+
+	if status["enabled"] == true && lp.charging() && lp.clock.Since(lp.speedEstimatorSnapshot) >= speedEstimatorSnapshotInterval {
+		lp.snapshotSpeedEstimator()
+	}
+}
+
+// snapshotSpeedEstimator stores the speed estimator session for resuming after restart
+func (lp *Loadpoint) snapshotSpeedEstimator() {
+	// unnamed vehicles are not resumed
+	if lp.speedEstimator == nil || lp.speedEstimatorVehicle == "" {
+		return
+	}
+
+	lp.speedEstimatorSnapshot = lp.clock.Now()
+
+	state := speedEstimatorState{
+		Vehicle:             lp.speedEstimatorVehicle,
+		SpeedEstimatorState: lp.speedEstimator.State(),
+	}
+	if lp.fusedEstimator != nil {
+		fused := lp.fusedEstimator.State()
+		state.Fused = &fused
+	}
+	if err := lp.settings.SetJson(keys.SpeedEstimatorState, state); err != nil {
+		lp.log.ERROR.Printf("speed estimator: store state: %v", err)
+	}
 }
 
 // publish charged energy and duration
@@ -1704,6 +1855,7 @@ func (lp *Loadpoint) publishSocAndRange() {
 	if socEstimator == nil || !lp.vehicleHasSoc() {
 		if soc, err := lp.chargerSoc(); err == nil {
 			lp.vehicleSoc = soc
+			lp.fusedSoc = false
 			lp.publish(keys.VehicleSoc, lp.vehicleSoc)
 
 			if vs, ok := lp.charger.(api.SocLimiter); ok {
@@ -1717,6 +1869,8 @@ func (lp *Loadpoint) publishSocAndRange() {
 			}
 		} else if !errors.Is(err, api.ErrNotAvailable) {
 			lp.log.ERROR.Printf("charger soc: %v", err)
+		} else {
+			lp.publishFusedSoc()
 		}
 
 		return
@@ -1783,6 +1937,40 @@ func (lp *Loadpoint) publishSocAndRange() {
 	}
 }
 
+// publishFusedSoc publishes the soc estimated from charging speed and charged energy
+// for vehicles without soc api
+func (lp *Loadpoint) publishFusedSoc() {
+	// keep a local copy in order to avoid race conditions
+	fusedEstimator, speedEstimator := lp.fusedEstimator, lp.speedEstimator
+	if fusedEstimator == nil || speedEstimator == nil {
+		return
+	}
+
+	f, ok := fusedEstimator.Soc(lp.GetChargedEnergy())
+	if !ok {
+		return
+	}
+
+	lp.vehicleSoc = f
+	lp.fusedSoc = true
+	lp.log.DEBUG.Printf("vehicle soc (estimated): %.0f%%", lp.vehicleSoc)
+	lp.publish(keys.VehicleSoc, lp.vehicleSoc)
+
+	// use minimum of speed estimator target and loadpoint
+	limitSoc := min(speedEstimator.TargetSoc(), lp.EffectiveLimitSoc())
+
+	var d time.Duration
+	if lp.charging() {
+		d = fusedEstimator.RemainingChargeDuration(limitSoc, lp.chargePower)
+	}
+	lp.SetRemainingDuration(d)
+
+	lp.SetRemainingEnergy(1e3 * fusedEstimator.RemainingChargeEnergy(limitSoc))
+
+	// trigger message after variables are updated
+	lp.bus.Publish(evVehicleSoc, f)
+}
+
 // addTask adds a single task to the queue
 func (lp *Loadpoint) addTask(task func()) {
 	// test guard
@@ -1933,11 +2121,16 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 		err = lp.setLimit(current)
 
 	// minimum or target charging
-	case lp.minSocNotReached() || plannerActive:
+	case lp.minSocNotReached():
 		err = lp.fastCharging()
 		lp.resetPhaseTimer()
 		lp.elapsePVTimer() // let PV mode disable immediately afterwards
 
+	case plannerActive:
+		err = lp.planCharging()
+		lp.resetPhaseTimer()
+		lp.elapsePVTimer() // let PV mode disable immediately afterwards
+
 	case lp.LimitEnergyReached():
 		lp.log.DEBUG.Printf("limitEnergy reached: %.0fkWh > %0.1fkWh", lp.GetChargedEnergy()/1e3, lp.limitEnergy)
 		err = lp.disableUnlessClimater()
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/planner"
 )
 
 //go:generate go tool mockgen -package loadpoint -destination mock.go -mock_names API=MockAPI github.com/evcc-io/evcc/core/loadpoint API
@@ -127,6 +128,8 @@ type API interface {
 	SocBasedPlanning() bool
 	// GetPlan creates a charging plan
 	GetPlan(targetTime time.Time, requiredDuration, precondition time.Duration) api.Rates
+	// GetPlanProfile creates a charging plan with per-slot charge power
+	GetPlanProfile(targetTime time.Time, requiredDuration, precondition time.Duration) planner.PowerPlan
 
 	// GetSocConfig returns the soc poll settings
 	GetSocConfig() SocConfig
@@ -200,6 +203,23 @@ type API interface {
 	// GetRemainingEnergy is the remaining charge energy in Wh
 	GetRemainingEnergy() float64
 
+	//
+	// charging speed-based soc estimation
+	//
+
+	// GetSpeedEstimatorStatus returns the speed estimator status of the active vehicle
+	GetSpeedEstimatorStatus() map[string]any
+	// SetSpeedEstimatorTargetSoc sets the speed estimator target soc of the active vehicle
+	SetSpeedEstimatorTargetSoc(soc int) error
+	// SetSpeedEstimatorReductionThreshold sets the speed estimator power reduction threshold of the active vehicle
+	SetSpeedEstimatorReductionThreshold(threshold float64) error
+	// SetSpeedEstimatorMinPower sets the speed estimator min power for estimation of the active vehicle
+	SetSpeedEstimatorMinPower(power float64) error
+	// ResetSpeedEstimator forgets the learned charging curve and session of the active vehicle
+	ResetSpeedEstimator() error
+	// CalibrateSpeedEstimator confirms the actual soc of the active vehicle
+	CalibrateSpeedEstimator(soc float64) error
+
 	//
 	// vehicles
 	//
@@ -14,6 +14,7 @@ import (
 	time "time"
 
 	api "github.com/evcc-io/evcc/api"
+	planner "github.com/evcc-io/evcc/core/planner"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +56,20 @@ func (mr *MockAPIMockRecorder) ActivePhases() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivePhases", reflect.TypeOf((*MockAPI)(nil).ActivePhases))
 }
 
+// CalibrateSpeedEstimator mocks base method.
+func (m *MockAPI) CalibrateSpeedEstimator(soc float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalibrateSpeedEstimator", soc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CalibrateSpeedEstimator indicates an expected call of CalibrateSpeedEstimator.
+func (mr *MockAPIMockRecorder) CalibrateSpeedEstimator(soc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalibrateSpeedEstimator", reflect.TypeOf((*MockAPI)(nil).CalibrateSpeedEstimator), soc)
+}
+
 // EffectiveMaxPower mocks base method.
 func (m *MockAPI) EffectiveMaxPower() float64 {
 	m.ctrl.T.Helper()
@@ -478,6 +493,20 @@ func (mr *MockAPIMockRecorder) GetPlanPreCondDuration() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanPreCondDuration", reflect.TypeOf((*MockAPI)(nil).GetPlanPreCondDuration))
 }
 
+// GetPlanProfile mocks base method.
+func (m *MockAPI) GetPlanProfile(targetTime time.Time, requiredDuration, precondition time.Duration) planner.PowerPlan {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlanProfile", targetTime, requiredDuration, precondition)
+	ret0, _ := ret[0].(planner.PowerPlan)
+	return ret0
+}
+
+// GetPlanProfile indicates an expected call of GetPlanProfile.
+func (mr *MockAPIMockRecorder) GetPlanProfile(targetTime, requiredDuration, precondition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanProfile", reflect.TypeOf((*MockAPI)(nil).GetPlanProfile), targetTime, requiredDuration, precondition)
+}
+
 // GetPlanRequiredDuration mocks base method.
 func (m *MockAPI) GetPlanRequiredDuration(goal, maxPower float64) time.Duration {
 	m.ctrl.T.Helper()
@@ -576,6 +605,20 @@ func (mr *MockAPIMockRecorder) GetSocConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocConfig", reflect.TypeOf((*MockAPI)(nil).GetSocConfig))
 }
 
+// GetSpeedEstimatorStatus mocks base method.
+func (m *MockAPI) GetSpeedEstimatorStatus() map[string]any {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpeedEstimatorStatus")
+	ret0, _ := ret[0].(map[string]any)
+	return ret0
+}
+
+// GetSpeedEstimatorStatus indicates an expected call of GetSpeedEstimatorStatus.
+func (mr *MockAPIMockRecorder) GetSpeedEstimatorStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpeedEstimatorStatus", reflect.TypeOf((*MockAPI)(nil).GetSpeedEstimatorStatus))
+}
+
 // GetStatus mocks base method.
 func (m *MockAPI) GetStatus() api.ChargeStatus {
 	m.ctrl.T.Helper()
@@ -684,6 +727,20 @@ func (mr *MockAPIMockRecorder) RemoteControl(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoteControl", reflect.TypeOf((*MockAPI)(nil).RemoteControl), arg0, arg1)
 }
 
+// ResetSpeedEstimator mocks base method.
+func (m *MockAPI) ResetSpeedEstimator() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetSpeedEstimator")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetSpeedEstimator indicates an expected call of ResetSpeedEstimator.
+func (mr *MockAPIMockRecorder) ResetSpeedEstimator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSpeedEstimator", reflect.TypeOf((*MockAPI)(nil).ResetSpeedEstimator))
+}
+
 // SetBatteryBoost mocks base method.
 func (m *MockAPI) SetBatteryBoost(enable bool) error {
 	m.ctrl.T.Helper()
@@ -946,6 +1003,48 @@ func (mr *MockAPIMockRecorder) SetSocConfig(soc any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSocConfig", reflect.TypeOf((*MockAPI)(nil).SetSocConfig), soc)
 }
 
+// SetSpeedEstimatorMinPower mocks base method.
+func (m *MockAPI) SetSpeedEstimatorMinPower(power float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpeedEstimatorMinPower", power)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSpeedEstimatorMinPower indicates an expected call of SetSpeedEstimatorMinPower.
+func (mr *MockAPIMockRecorder) SetSpeedEstimatorMinPower(power any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpeedEstimatorMinPower", reflect.TypeOf((*MockAPI)(nil).SetSpeedEstimatorMinPower), power)
+}
+
+// SetSpeedEstimatorReductionThreshold mocks base method.
+func (m *MockAPI) SetSpeedEstimatorReductionThreshold(threshold float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpeedEstimatorReductionThreshold", threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSpeedEstimatorReductionThreshold indicates an expected call of SetSpeedEstimatorReductionThreshold.
+func (mr *MockAPIMockRecorder) SetSpeedEstimatorReductionThreshold(threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpeedEstimatorReductionThreshold", reflect.TypeOf((*MockAPI)(nil).SetSpeedEstimatorReductionThreshold), threshold)
+}
+
+// SetSpeedEstimatorTargetSoc mocks base method.
+func (m *MockAPI) SetSpeedEstimatorTargetSoc(soc int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpeedEstimatorTargetSoc", soc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSpeedEstimatorTargetSoc indicates an expected call of SetSpeedEstimatorTargetSoc.
+func (mr *MockAPIMockRecorder) SetSpeedEstimatorTargetSoc(soc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpeedEstimatorTargetSoc", reflect.TypeOf((*MockAPI)(nil).SetSpeedEstimatorTargetSoc), soc)
+}
+
 // SetThresholds mocks base method.
 func (m *MockAPI) SetThresholds(thresholds ThresholdsConfig) {
 	m.ctrl.T.Helper()
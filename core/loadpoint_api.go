@@ -6,13 +6,17 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/circuit"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/settings"
 	"github.com/evcc-io/evcc/core/wrapper"
 )
 
-var _ loadpoint.API = (*Loadpoint)(nil)
+var (
+	_ loadpoint.API    = (*Loadpoint)(nil)
+	_ circuit.Consumer = (*Loadpoint)(nil)
+)
 
 func (lp *Loadpoint) isConfigurable() bool {
 	_, ok := lp.settings.(*settings.ConfigSettings)
@@ -698,6 +702,11 @@ func (lp *Loadpoint) SetMaxCurrent(current float64) error {
 	lp.log.DEBUG.Println("set max current:", current)
 	if current != lp.maxCurrent {
 		lp.setMaxCurrent(current)
+
+		// power change is not caused by the vehicle
+		if lp.speedEstimator != nil {
+			lp.speedEstimator.ResetReference("max current change")
+		}
 	}
 
 	return nil
@@ -842,3 +851,23 @@ func (lp *Loadpoint) GetCircuit() api.Circuit {
 
 	return lp.circuit
 }
+
+// GetCircuitDemand implements the circuit.Consumer interface
+func (lp *Loadpoint) GetCircuitDemand() circuit.Demand {
+	if !lp.connected() || lp.GetMode() == api.ModeOff {
+		return circuit.Demand{}
+	}
+
+	lp.RLock()
+	res := circuit.Demand{
+		Current:    lp.requestedCurrent,
+		MinCurrent: lp.effectiveMinCurrent(),
+		Soc:        lp.vehicleSoc,
+	}
+	lp.RUnlock()
+
+	res.Priority = lp.EffectivePriority()
+	res.PlanTime = lp.EffectivePlanTime()
+
+	return res
+}
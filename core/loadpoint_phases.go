@@ -162,3 +162,59 @@ func (lp *Loadpoint) hasPhaseSwitching() bool {
 	_, ok := lp.charger.(api.PhaseSwitcher)
 	return ok
 }
+
+// circuitPhases returns the circuit phases (1-3) used for given number of active phases or nil if unknown
+func (lp *Loadpoint) circuitPhases(phases int) []int {
+	if lp.CircuitPhase == 0 {
+		return nil
+	}
+
+	res := make([]int, 0, phases)
+	for i := range phases {
+		res = append(res, (lp.CircuitPhase-1+i)%3+1)
+	}
+
+	return res
+}
+
+// imbalanceLimited checks if single phase charging at given current is limited by the circuit's phase imbalance
+func (lp *Loadpoint) imbalanceLimited(current float64) bool {
+	phases := lp.circuitPhases(1)
+	if lp.circuit == nil || phases == nil || current <= 0 {
+		return false
+	}
+
+	// the current single phase load already counts towards the imbalance
+	var old float64
+	if lp.ActivePhases() == 1 {
+		old = lp.GetMaxPhaseCurrent()
+	}
+
+	return lp.circuit.ValidateImbalance(phases, old, current) < current
+}
+
+// Currents implements the api.PhaseCurrents interface.
+// Returns the charge currents on the circuit's phases if the circuit phase is configured.
+func (lp *Loadpoint) Currents() (float64, float64, float64, error) {
+	lp.RLock()
+	defer lp.RUnlock()
+
+	if lp.CircuitPhase == 0 {
+		return 0, 0, 0, api.ErrNotAvailable
+	}
+
+	currents := lp.chargeCurrents
+	if currents == nil {
+		currents = make([]float64, lp.activePhases())
+		for i := range currents {
+			currents[i] = lp.offeredCurrent
+		}
+	}
+
+	var res [3]float64
+	for i, current := range currents {
+		res[(lp.CircuitPhase-1+i)%3] += current
+	}
+
+	return res[0], res[1], res[2], nil
+}
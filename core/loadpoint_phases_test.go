@@ -477,3 +477,22 @@ func TestScalePhasesIfAvailable(t *testing.T) {
 		ctrl.Finish()
 	}
 }
+
+func TestCircuitPhases(t *testing.T) {
+	lp := &Loadpoint{log: util.NewLogger("foo")}
+
+	// unknown connection
+	require.Nil(t, lp.circuitPhases(1))
+	_, _, _, err := lp.Currents()
+	require.ErrorIs(t, err, api.ErrNotAvailable)
+
+	// charger L1 connected to circuit L2
+	lp.CircuitPhase = 2
+	require.Equal(t, []int{2}, lp.circuitPhases(1))
+	require.Equal(t, []int{2, 3, 1}, lp.circuitPhases(3))
+
+	lp.chargeCurrents = []float64{16, 0, 0}
+	i1, i2, i3, err := lp.Currents()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 16, 0}, []float64{i1, i2, i3})
+}
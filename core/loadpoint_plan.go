@@ -21,6 +21,7 @@ const (
 func (lp *Loadpoint) setPlanActive(active bool) {
 	if !active {
 		lp.planSlotEnd = time.Time{}
+		lp.planPower = 0
 	}
 	if lp.planActive != active {
 		lp.planActive = active
@@ -53,6 +54,13 @@ func (lp *Loadpoint) GetPlanRequiredDuration(goal, maxPower float64) time.Durati
 
 // getPlanRequiredDuration is the estimated total charging duration
 func (lp *Loadpoint) getPlanRequiredDuration(goal, maxPower float64) time.Duration {
+	if lp.fusedSocPlanning() {
+		// charging stops at the speed estimator target
+		soc := min(int(goal), lp.speedEstimator.TargetSoc())
+		d, _ := lp.fusedEstimator.PlanDuration(lp.fusedEstimator.RemainingChargeEnergy(soc), maxPower)
+		return d
+	}
+
 	if lp.socBasedPlanning() {
 		if lp.socEstimator == nil {
 			return 0
@@ -61,6 +69,14 @@ func (lp *Loadpoint) getPlanRequiredDuration(goal, maxPower float64) time.Durati
 	}
 
 	energy := lp.remainingPlanEnergy(goal)
+
+	// learned charging taper refines the duration once the estimated soc is known
+	if fusedEstimator := lp.fusedEstimator; fusedEstimator != nil && lp.fusedSoc {
+		if d, ok := fusedEstimator.PlanDuration(energy, maxPower); ok {
+			return d
+		}
+	}
+
 	return time.Duration(energy * 1e3 / maxPower * float64(time.Hour))
 }
 
@@ -95,11 +111,58 @@ func (lp *Loadpoint) GetPlanPreCondDuration() time.Duration {
 // GetPlan creates a charging plan for given time and duration
 // The plan is sorted by time
 func (lp *Loadpoint) GetPlan(targetTime time.Time, requiredDuration, precondition time.Duration) api.Rates {
-	if lp.planner == nil || targetTime.IsZero() {
+	return lp.GetPlanProfile(targetTime, requiredDuration, precondition).Rates()
+}
+
+// GetPlanProfile creates a charging plan with per-slot charge power for given time and duration
+// The plan is sorted by time
+func (lp *Loadpoint) GetPlanProfile(targetTime time.Time, requiredDuration, precondition time.Duration) planner.PowerPlan {
+	if targetTime.IsZero() {
+		return nil
+	}
+
+	// share of the plan of all loadpoints
+	if lp.sitePlanner != nil {
+		return lp.sitePlanner.Plan(lp, lp.planRequest(targetTime, requiredDuration, precondition))
+	}
+
+	if lp.planner == nil {
 		return nil
 	}
 
-	return lp.planner.Plan(requiredDuration, precondition, targetTime)
+	return planner.NewPowerPlan(lp.planner.Plan(requiredDuration, precondition, targetTime), lp.EffectiveMaxPower())
+}
+
+// planRequest returns the site planner request for given plan
+func (lp *Loadpoint) planRequest(targetTime time.Time, requiredDuration, precondition time.Duration) planner.Request {
+	return planner.Request{
+		Priority:         lp.EffectivePriority(),
+		TargetTime:       targetTime,
+		RequiredDuration: requiredDuration,
+		Precondition:     precondition,
+		MaxPower:         lp.EffectiveMaxPower(),
+		MinPower:         lp.EffectiveMinPower(),
+	}
+}
+
+// activePlanRequest returns the site planner request of the current plan
+func (lp *Loadpoint) activePlanRequest() (planner.Request, bool) {
+	if !lp.connected() || lp.GetMode() == api.ModeOff {
+		return planner.Request{}, false
+	}
+
+	planTime := lp.EffectivePlanTime()
+	if planTime.IsZero() {
+		return planner.Request{}, false
+	}
+
+	goal, _ := lp.GetPlanGoal()
+	requiredDuration := lp.GetPlanRequiredDuration(goal, lp.EffectiveMaxPower())
+	if requiredDuration <= 0 {
+		return planner.Request{}, false
+	}
+
+	return lp.planRequest(planTime, requiredDuration, lp.GetPlanPreCondDuration()), true
 }
 
 // plannerActive checks if the charging plan has a currently active slot
@@ -117,6 +180,9 @@ func (lp *Loadpoint) plannerActive() (active bool) {
 		lp.publish(keys.PlanOverrun, planOverrun)
 	}()
 
+	// full power unless the active slot is planned partially
+	lp.planPower = 0
+
 	// re-check since plannerActive() is called before connected() check in Update()
 	if !lp.connected() {
 		return false
@@ -147,7 +213,8 @@ func (lp *Loadpoint) plannerActive() (active bool) {
 		return false
 	}
 
-	plan := lp.GetPlan(planTime, requiredDuration, lp.GetPlanPreCondDuration())
+	profile := lp.GetPlanProfile(planTime, requiredDuration, lp.GetPlanPreCondDuration())
+	plan := profile.Rates()
 	if plan == nil {
 		return false
 	}
@@ -181,6 +248,12 @@ func (lp *Loadpoint) plannerActive() (active bool) {
 
 		// remember last active plan's slot end time
 		lp.planSlotEnd = activeSlot.End
+
+		// charge at reduced power if the slot is planned partially
+		if power := profile.SlotAt(lp.clock.Now()).Power; power < maxPower {
+			lp.planPower = power
+			lp.log.DEBUG.Printf("plan: slot power %.0fW", power)
+		}
 	} else if lp.planActive {
 		// planner was active (any slot, not necessarily previous slot) and charge goal has not yet been met
 		switch {
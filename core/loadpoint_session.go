@@ -4,6 +4,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/soc"
 	"github.com/jinzhu/now"
 	"github.com/samber/lo"
 )
@@ -86,6 +87,47 @@ func (lp *Loadpoint) stopSession() {
 	lp.db.Persist(s)
 }
 
+// recordSessionCurve adds a sample to the charging curve of the session.
+// Samples are taken at the speed estimator sample interval once the session has been persisted.
+func (lp *Loadpoint) recordSessionCurve(power float64, phases int) {
+	// test guard
+	if lp.db == nil || lp.session == nil || lp.session.ID == 0 {
+		return
+	}
+
+	// keep a local copy in order to avoid race conditions
+	speedEstimator := lp.speedEstimator
+
+	interval := soc.DefaultChargingSpeedConfig().SampleInterval
+	if speedEstimator != nil {
+		interval = speedEstimator.Config().SampleInterval
+	}
+
+	now := lp.clock.Now()
+	if now.Sub(lp.sessionSample) < interval {
+		return
+	}
+	lp.sessionSample = now
+
+	p := session.CurvePoint{
+		SessionID:      lp.session.ID,
+		Timestamp:      now,
+		Power:          power,
+		Phases:         phases,
+		OfferedCurrent: lp.offeredCurrent,
+	}
+
+	if lp.vehicleSoc > 0 {
+		p.Soc = lo.ToPtr(lp.vehicleSoc)
+	}
+
+	if speedEstimator != nil && speedEstimator.IsEstimationActive() {
+		p.EstimatedSoc = lo.ToPtr(speedEstimator.GetEstimatedSoc())
+	}
+
+	lp.db.PersistCurvePoint(p)
+}
+
 type sessionOption func(*session.Session)
 
 // updateSession updates any parameter of a charging session and persists the session.
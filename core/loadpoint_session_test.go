@@ -91,6 +91,46 @@ func TestSession(t *testing.T) {
 	t.Logf("session: %+v", s)
 }
 
+func TestSessionCurve(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	db, err := session.NewStore("foo", serverdb.Instance)
+	require.NoError(t, err)
+
+	clock := clock.NewMock()
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		clock:          clock,
+		db:             db,
+		offeredCurrent: 16,
+	}
+
+	// not recorded before session is persisted
+	lp.session = db.New(0)
+	lp.recordSessionCurve(11000, 3)
+
+	lp.updateSession(sessionStart(lp))
+	require.NotZero(t, lp.session.ID)
+
+	for _, power := range []float64{11000, 10000, 9000} {
+		lp.recordSessionCurve(power, 3)
+		lp.recordSessionCurve(power, 3) // rate limited
+		clock.Add(30 * time.Second)
+	}
+
+	curve, err := db.Curve(lp.session.ID)
+	require.NoError(t, err)
+	require.Len(t, curve, 3)
+
+	assert.Equal(t, 9000.0, curve[2].Power)
+	assert.Equal(t, 3, curve[2].Phases)
+	assert.Equal(t, 16.0, curve[2].OfferedCurrent)
+	assert.Nil(t, curve[2].Soc)
+}
+
 func TestCloseSessionsOnStartup_emptyDb(t *testing.T) {
 	var err error
 	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/core/keys"
+)
+
+var errSpeedEstimatorUnavailable = errors.New("speed estimator not available")
+
+// GetSpeedEstimatorStatus returns the speed estimator status of the active vehicle
+func (lp *Loadpoint) GetSpeedEstimatorStatus() map[string]any {
+	// keep a local copy in order to avoid race conditions
+	speedEstimator := lp.speedEstimator
+	if speedEstimator == nil {
+		return nil
+	}
+
+	return speedEstimator.GetStatus()
+}
+
+// SetSpeedEstimatorTargetSoc sets the speed estimator target soc of the active vehicle
+func (lp *Loadpoint) SetSpeedEstimatorTargetSoc(soc int) error {
+	speedEstimator := lp.speedEstimator
+	if speedEstimator == nil {
+		return errSpeedEstimatorUnavailable
+	}
+
+	lp.log.DEBUG.Println("set speed estimator target soc:", soc)
+
+	if err := speedEstimator.SetTargetSoc(soc); err != nil {
+		return err
+	}
+
+	lp.publish(keys.SpeedEstimatorTargetSoc, soc)
+	lp.requestUpdate()
+
+	return nil
+}
+
+// SetSpeedEstimatorReductionThreshold sets the speed estimator power reduction threshold of the active vehicle
+func (lp *Loadpoint) SetSpeedEstimatorReductionThreshold(threshold float64) error {
+	speedEstimator := lp.speedEstimator
+	if speedEstimator == nil {
+		return errSpeedEstimatorUnavailable
+	}
+
+	lp.log.DEBUG.Println("set speed estimator reduction threshold:", threshold)
+
+	return speedEstimator.SetReductionThreshold(threshold)
+}
+
+// SetSpeedEstimatorMinPower sets the speed estimator min power for estimation of the active vehicle
+func (lp *Loadpoint) SetSpeedEstimatorMinPower(power float64) error {
+	speedEstimator := lp.speedEstimator
+	if speedEstimator == nil {
+		return errSpeedEstimatorUnavailable
+	}
+
+	lp.log.DEBUG.Println("set speed estimator min power:", power)
+
+	return speedEstimator.SetMinPowerForEstimation(power)
+}
+
+// ResetSpeedEstimator forgets the learned charging curve and session of the active vehicle
+func (lp *Loadpoint) ResetSpeedEstimator() error {
+	speedEstimator, fusedEstimator := lp.speedEstimator, lp.fusedEstimator
+	if speedEstimator == nil {
+		return errSpeedEstimatorUnavailable
+	}
+
+	lp.log.DEBUG.Println("reset speed estimator")
+
+	speedEstimator.ResetCurve()
+	speedEstimator.Reset()
+	if fusedEstimator != nil {
+		fusedEstimator.Reset()
+	}
+
+	lp.settings.SetString(keys.SpeedEstimatorState, "")
+	lp.requestUpdate()
+
+	return nil
+}
+
+// CalibrateSpeedEstimator confirms the actual soc of the active vehicle.
+// The current session is merged into the learned charging curve and the continuous soc estimate is anchored.
+func (lp *Loadpoint) CalibrateSpeedEstimator(soc float64) error {
+	speedEstimator, fusedEstimator := lp.speedEstimator, lp.fusedEstimator
+	if speedEstimator == nil {
+		return errSpeedEstimatorUnavailable
+	}
+
+	if soc < 0 || soc > 100 {
+		return errors.New("invalid soc")
+	}
+
+	lp.log.DEBUG.Printf("calibrate speed estimator: %.0f%%", soc)
+
+	speedEstimator.Calibrate(soc)
+	if fusedEstimator != nil {
+		fusedEstimator.Calibrate(soc, lp.GetChargedEnergy())
+	}
+
+	lp.requestUpdate()
+
+	return nil
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"slices"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/settings"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/util"
@@ -156,6 +158,8 @@ func (lp *Loadpoint) setActiveVehicle(v api.Vehicle) {
 	} else {
 		lp.socEstimator = nil
 		lp.speedEstimator = nil
+		lp.fusedEstimator = nil
+		lp.speedEstimatorVehicle = ""
 		lp.unpublishVehicleIdentity()
 	}
 
@@ -210,6 +214,7 @@ func (lp *Loadpoint) unpublishVehicleIdentity() {
 // unpublishVehicle resets published vehicle data
 func (lp *Loadpoint) unpublishVehicle() {
 	lp.vehicleSoc = 0
+	lp.fusedSoc = false
 
 	lp.publish(keys.VehicleClimaterActive, nil)
 	lp.publish(keys.VehicleSoc, 0.0)
@@ -384,6 +389,13 @@ func (lp *Loadpoint) vehicleClimateActive() bool {
 	return false
 }
 
+// speedEstimatorState is the speed estimator session snapshot of the loadpoint
+type speedEstimatorState struct {
+	Vehicle string                   `json:"vehicle"`
+	Fused   *soc.FusedEstimatorState `json:"fused,omitempty"`
+	soc.SpeedEstimatorState
+}
+
 // initializeSpeedEstimator creates and configures the speed estimator for the given vehicle
 func (lp *Loadpoint) initializeSpeedEstimator(v api.Vehicle) {
 	// Check if vehicle has charging speed limit configuration
@@ -410,6 +422,13 @@ func (lp *Loadpoint) initializeSpeedEstimator(v api.Vehicle) {
 		if minPower, ok := config["minPowerForEstimation"].(float64); ok && minPower > 0 {
 			speedConfig.MinPowerForEstimation = minPower
 		}
+		// zero is a valid reference temperature, unset if missing
+		if reference, ok := config["temperatureReference"].(float64); ok {
+			speedConfig.TemperatureReference = reference
+		}
+		if coefficient, ok := config["temperatureCoefficient"].(float64); ok && coefficient > 0 {
+			speedConfig.TemperatureCoefficient = coefficient
+		}
 
 		// Parse duration strings
 		if maxPowerWindow, ok := config["maxPowerWindow"].(string); ok && maxPowerWindow != "" {
@@ -437,15 +456,59 @@ func (lp *Loadpoint) initializeSpeedEstimator(v api.Vehicle) {
 				speedConfig.StabilityWindow = duration
 			}
 		}
+		if trickleDuration, ok := config["trickleDuration"].(string); ok && trickleDuration != "" {
+			if duration, err := time.ParseDuration(trickleDuration); err == nil {
+				speedConfig.TrickleDuration = duration
+			}
+		}
+
+		// Capacity is required for learning the charging curve
+		speedConfig.Capacity = v.Capacity()
 
 		// Create the speed estimator
 		lp.speedEstimator = soc.NewSpeedEstimator(lp.log, speedConfig)
 
+		// Optional ambient temperature moves the expected taper for cold batteries
+		if temperatureG, ok := config["temperature"].(func() (float64, error)); ok {
+			lp.speedEstimator.SetTemperature(temperatureG)
+		}
+
+		// Continuous soc estimate requires the vehicle capacity
+		lp.fusedEstimator = nil
+		if speedConfig.Enabled && speedConfig.Capacity > 0 {
+			lp.fusedEstimator = soc.NewFusedEstimator(lp.log, lp.speedEstimator, speedConfig.Capacity)
+		}
+
+		// Restore learned charging curve of the vehicle, unnamed vehicles are stored with the loadpoint
+		name := vehicle.Settings(lp.log, v).Name()
+		if name != "" {
+			lp.speedEstimator.SetStore(settings.NewDatabaseSettingsAdapter(fmt.Sprintf("vehicle.%s.", name)))
+		} else {
+			lp.log.DEBUG.Println("speed estimator: unnamed vehicle, storing settings with loadpoint")
+			lp.speedEstimator.SetStore(lp.settings)
+		}
+		lp.speedEstimatorVehicle = name
+
+		// resume session interrupted by restart, unnamed vehicles can't be identified
+		var state speedEstimatorState
+		if err := lp.settings.Json(keys.SpeedEstimatorState, &state); err == nil && name != "" && state.Vehicle == name && lp.speedEstimator.Restore(state.SpeedEstimatorState) {
+			if lp.fusedEstimator != nil && state.Fused != nil {
+				lp.fusedEstimator.Restore(*state.Fused)
+			}
+			lp.speedEstimatorResumed = true
+
+			lp.log.INFO.Println("speed estimator: resumed session")
+		}
+
 		if speedConfig.Enabled {
+			// runtime settings of the vehicle may override its configuration
+			effective := lp.speedEstimator.Config()
 			lp.log.INFO.Printf("speed estimator enabled: target SoC %d%%, reduction threshold %.1f%%",
-				speedConfig.TargetSoc, speedConfig.ReductionThreshold*100)
+				effective.TargetSoc, effective.ReductionThreshold*100)
 		}
 	} else {
 		lp.speedEstimator = nil
+		lp.fusedEstimator = nil
+		lp.speedEstimatorVehicle = ""
 	}
 }
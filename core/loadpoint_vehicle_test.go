@@ -9,10 +9,13 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/settings"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -240,7 +243,10 @@ func TestReconnectVehicle(t *testing.T) {
 			vehicle.MockVehicle.EXPECT().Soc().Return(0.0, nil).AnyTimes()
 
 			charger := api.NewMockCharger(ctrl)
-			charger.EXPECT().Status().Return(api.StatusB, nil).AnyTimes()
+			status := api.StatusB
+			charger.EXPECT().Status().DoAndReturn(func() (api.ChargeStatus, error) {
+				return status, nil
+			}).AnyTimes()
 
 			lp := &Loadpoint{
 				log:         util.NewLogger("foo"),
@@ -291,3 +297,121 @@ func TestReconnectVehicle(t *testing.T) {
 		})
 	}
 }
+
+func TestFusedSocPlanning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(10.0).AnyTimes()
+	vehicle.EXPECT().Features().Return([]api.Feature{api.Offline}).AnyTimes()
+
+	log := util.NewLogger("foo")
+	se := soc.NewSpeedEstimator(log, soc.DefaultChargingSpeedConfig())
+
+	lp := NewLoadpoint(log, nil)
+	lp.vehicle = vehicle
+	lp.speedEstimator = se
+	lp.fusedEstimator = soc.NewFusedEstimator(log, se, 10)
+
+	// soc unknown
+	lp.publishFusedSoc()
+	assert.False(t, lp.socBasedPlanning())
+
+	// soc confirmed manually
+	lp.fusedEstimator.Calibrate(40, 0)
+	lp.publishFusedSoc()
+	assert.True(t, lp.socBasedPlanning())
+
+	// 40% of 10kWh at 11kW
+	d := lp.GetPlanRequiredDuration(80, 11e3)
+	assert.Greater(t, d, 20*time.Minute)
+	assert.Less(t, d, 40*time.Minute)
+
+	// energy plans take precedence
+	lp.planEnergy = 5
+	assert.False(t, lp.socBasedPlanning())
+}
+
+type speedLimitVehicle struct {
+	*api.MockVehicle
+}
+
+func (v *speedLimitVehicle) GetChargingSpeedLimitConfig() map[string]any {
+	return map[string]any{"enabled": true}
+}
+
+func TestSpeedEstimatorResumeAfterRestart(t *testing.T) {
+	for _, name := range []string{"car", ""} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mv := api.NewMockVehicle(ctrl)
+			mv.EXPECT().GetTitle().Return("car").AnyTimes()
+			mv.EXPECT().Capacity().Return(10.0).AnyTimes()
+			mv.EXPECT().Icon().AnyTimes()
+			mv.EXPECT().Features().Return([]api.Feature{api.Offline}).AnyTimes()
+			mv.EXPECT().Phases().AnyTimes()
+			mv.EXPECT().OnIdentified().AnyTimes()
+			vehicle := &speedLimitVehicle{mv}
+
+			if name != "" {
+				require.NoError(t, config.Vehicles().Add(config.NewStaticDevice(config.Named{Name: name}, api.Vehicle(vehicle))))
+				t.Cleanup(func() { _ = config.Vehicles().Delete(name) })
+			}
+
+			charger := api.NewMockCharger(ctrl)
+
+			lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("resume."+name))
+			lp.charger = charger
+			lp.chargeMeter = &Null{}
+			lp.chargeRater = &Null{}
+			lp.chargeTimer = &Null{}
+			lp.wakeUpTimer = NewTimer()
+			lp.defaultVehicle = vehicle
+
+			// session stored before restart
+			startSoc := 30.0
+			now := time.Now()
+			require.NoError(t, lp.settings.SetJson(keys.SpeedEstimatorState, speedEstimatorState{
+				Vehicle: name,
+				Fused:   &soc.FusedEstimatorState{StartSoc: &startSoc, VehicleSoc: 50},
+				SpeedEstimatorState: soc.SpeedEstimatorState{
+					PowerHistory:    []soc.PowerMeasurement{{Timestamp: now, Power: 11e3}},
+					MaxPower:        11e3,
+					ChargingStarted: now.Add(-time.Hour),
+				},
+			}))
+
+			attachListeners(t, lp)
+
+			// first update after restart connects the vehicle
+			charger.EXPECT().Enabled().Return(true, nil).AnyTimes()
+			status := api.StatusB
+			charger.EXPECT().Status().DoAndReturn(func() (api.ChargeStatus, error) {
+				return status, nil
+			}).AnyTimes()
+			charger.EXPECT().Enable(gomock.Any()).AnyTimes()
+			charger.EXPECT().MaxCurrent(gomock.Any()).AnyTimes()
+			lp.Update(0, 0, nil, nil, false, false, 0, nil, nil)
+
+			require.NotNil(t, lp.fusedEstimator)
+			state := lp.fusedEstimator.State()
+
+			if name == "" {
+				// unnamed vehicle does not resume the previous session
+				assert.Nil(t, state.StartSoc)
+				return
+			}
+
+			require.NotNil(t, state.StartSoc)
+			assert.Equal(t, startSoc, *state.StartSoc)
+
+			// real disconnect and reconnect forgets the session
+			status = api.StatusA
+			lp.Update(0, 0, nil, nil, false, false, 0, nil, nil)
+			status = api.StatusB
+			lp.Update(0, 0, nil, nil, false, false, 0, nil, nil)
+			assert.Nil(t, lp.fusedEstimator.State().StartSoc)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package peak
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Interval is the billing interval of capacity tariffs
+const Interval = 15 * time.Minute
+
+// Peak is the highest average grid import of an interval
+type Peak struct {
+	Start time.Time `json:"start"` // start of the interval
+	Power float64   `json:"power"` // W average import
+}
+
+// Status is the peak shaving state
+type Status struct {
+	Peak    Peak    `json:"peak"`    // month's peak
+	Average float64 `json:"average"` // W average import of the current interval so far
+	Target  float64 `json:"target"`  // W max average import
+	Limit   float64 `json:"limit"`   // W max import for the remainder of the current interval
+}
+
+// Tracker tracks the average grid import per interval and the month's peak
+type Tracker struct {
+	clock   clock.Clock
+	start   time.Time // start of the current interval
+	partial bool      // current interval not observed from its start
+	updated time.Time
+	power   float64 // W last import power
+	energy  float64 // Wh imported in the current interval
+	peak    Peak    // month's peak
+}
+
+// New creates a peak tracker
+func New() *Tracker {
+	return &Tracker{
+		clock: clock.New(),
+	}
+}
+
+// Restore restores the month's peak
+func (t *Tracker) Restore(peak Peak) {
+	if sameMonth(peak.Start, t.clock.Now()) {
+		t.peak = peak
+	}
+}
+
+// Peak returns the month's peak
+func (t *Tracker) Peak() Peak {
+	return t.peak
+}
+
+// Update adds the current grid power. It returns true if the month's peak has changed.
+func (t *Tracker) Update(power float64) bool {
+	now := t.clock.Now()
+
+	var changed bool
+
+	// new month
+	if !t.peak.Start.IsZero() && !sameMonth(t.peak.Start, now) {
+		t.peak = Peak{}
+		changed = true
+	}
+
+	if t.updated.IsZero() || now.Sub(t.updated) > Interval {
+		// (re)start tracking within the interval
+		t.start = now.Truncate(Interval)
+		t.partial = now.After(t.start)
+		t.energy = 0
+	} else {
+		// complete elapsed intervals
+		for end := t.start.Add(Interval); !now.Before(end); end = t.start.Add(Interval) {
+			t.energy += t.power * end.Sub(t.updated).Hours()
+			changed = t.complete() || changed
+
+			t.start = end
+			t.updated = end
+			t.partial = false
+			t.energy = 0
+		}
+
+		t.energy += t.power * now.Sub(t.updated).Hours()
+	}
+
+	t.updated = now
+	t.power = max(0, power)
+
+	return changed
+}
+
+// complete updates the month's peak from the completed interval
+func (t *Tracker) complete() bool {
+	if t.partial {
+		return false
+	}
+
+	avg := t.energy / Interval.Hours()
+	if avg <= t.peak.Power && sameMonth(t.peak.Start, t.start) {
+		return false
+	}
+
+	t.peak = Peak{Start: t.start, Power: avg}
+
+	return true
+}
+
+// Average returns the average import of the current interval so far
+func (t *Tracker) Average() float64 {
+	elapsed := t.updated.Sub(t.start)
+	if elapsed <= 0 {
+		return t.power
+	}
+	return t.energy / elapsed.Hours()
+}
+
+// Limit returns the max import power for the remainder of the current interval
+// keeping the interval's average import at or below the target
+func (t *Tracker) Limit(target float64) float64 {
+	remaining := t.start.Add(Interval).Sub(t.updated)
+	if remaining <= 0 {
+		return target
+	}
+
+	return max(0, (target*Interval.Hours()-t.energy)/remaining.Hours())
+}
+
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
@@ -0,0 +1,72 @@
+package peak
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 1, 31, 22, 40, 0, 0, time.UTC))
+
+	tr := New()
+	tr.clock = clock
+
+	// partial interval is not considered
+	assert.False(t, tr.Update(10e3))
+	clock.Add(5 * time.Minute)
+	assert.False(t, tr.Update(2e3))
+	assert.Equal(t, Peak{}, tr.Peak())
+
+	// 10 minutes at 2kW
+	clock.Add(10 * time.Minute)
+	assert.False(t, tr.Update(5e3))
+	assert.Equal(t, 2e3, tr.Average())
+
+	// remaining 5 minutes at max 5kW for 3kW average
+	assert.InDelta(t, 5e3, tr.Limit(3e3), 1e-6)
+
+	clock.Add(5 * time.Minute)
+	assert.True(t, tr.Update(-5e3))
+	assert.Equal(t, time.Date(2025, 1, 31, 22, 45, 0, 0, time.UTC), tr.Peak().Start)
+	assert.InDelta(t, 3e3, tr.Peak().Power, 1e-6)
+
+	// export does not count
+	clock.Add(15 * time.Minute)
+	assert.False(t, tr.Update(0))
+	assert.InDelta(t, 3e3, tr.Peak().Power, 1e-6)
+
+	// new month
+	clock.Add(45 * time.Minute)
+	assert.True(t, tr.Update(1e3))
+	assert.Equal(t, Peak{}, tr.Peak())
+
+	// budget exhausted
+	clock.Add(10 * time.Minute)
+	assert.False(t, tr.Update(1e3))
+	assert.Equal(t, 0.0, tr.Limit(500))
+	assert.InDelta(t, 1e3, tr.Limit(1e3), 1e-6)
+
+	clock.Add(5 * time.Minute)
+	assert.True(t, tr.Update(0))
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), tr.Peak().Start)
+	assert.InDelta(t, 1e3, tr.Peak().Power, 1e-6)
+}
+
+func TestTrackerRestore(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 2, 10, 12, 0, 0, 0, time.UTC))
+
+	tr := New()
+	tr.clock = clock
+
+	tr.Restore(Peak{Start: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), Power: 5e3})
+	assert.Equal(t, Peak{}, tr.Peak())
+
+	peak := Peak{Start: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC), Power: 5e3}
+	tr.Restore(peak)
+	assert.Equal(t, peak, tr.Peak())
+}
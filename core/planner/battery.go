@@ -0,0 +1,235 @@
+package planner
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	DefaultBatteryEfficiency = 0.9 // round-trip efficiency
+
+	batteryLevels = 100 // soc resolution of the optimization
+)
+
+// Battery describes the home battery to be planned
+type Battery struct {
+	Capacity          float64 // kWh
+	Soc               float64 // % current soc
+	Efficiency        float64 // round-trip efficiency (0..1)
+	MaxChargePower    float64 // W, defaults to 1C
+	MaxDischargePower float64 // W, defaults to 1C
+}
+
+// BatterySlot is a battery plan slot with the planned battery mode
+type BatterySlot struct {
+	api.Rate                 // grid price
+	Mode     api.BatteryMode `json:"mode"`
+	Power    float64         `json:"power"` // W expected battery power (charge negative, discharge positive)
+	Soc      float64         `json:"soc"`   // % expected soc at the end of the slot
+	Grid     float64         `json:"grid"`  // W expected grid power (import positive, export negative)
+}
+
+// BatteryPlan is the planned battery operation over the tariff horizon
+type BatteryPlan []BatterySlot
+
+// SlotAt returns the slot for the given time or an empty slot
+func (p BatteryPlan) SlotAt(ts time.Time) BatterySlot {
+	for _, slot := range p {
+		if !slot.Start.After(ts) && slot.End.After(ts) {
+			return slot
+		}
+	}
+	return BatterySlot{}
+}
+
+// BatteryPlanner plans when to grid-charge, hold or discharge the home battery
+type BatteryPlanner struct {
+	log      *util.Logger
+	clock    clock.Clock // mockable time
+	tariff   api.Tariff  // grid price
+	feedin   api.Tariff  // feed-in price
+	solar    api.Tariff  // solar forecast in W
+	baseload Forecast    // expected household consumption in W
+}
+
+// NewBatteryPlanner creates a battery planner
+func NewBatteryPlanner(log *util.Logger, tariff, feedin, solar api.Tariff, baseload Forecast) *BatteryPlanner {
+	return &BatteryPlanner{
+		log:      log,
+		clock:    clock.New(),
+		tariff:   tariff,
+		feedin:   feedin,
+		solar:    solar,
+		baseload: baseload,
+	}
+}
+
+// batteryStep is the outcome of operating the battery in a given mode for one slot
+type batteryStep struct {
+	mode   api.BatteryMode
+	energy float64 // Wh stored at the end of the slot
+	power  float64 // W battery power (charge negative, discharge positive)
+	grid   float64 // W grid power
+	cost   float64
+}
+
+// Plan optimizes the battery mode of each grid tariff slot for lowest total energy cost.
+// In normal mode the battery covers the household deficit and stores solar surplus, in hold mode it is
+// not discharged and in charge mode it is charged at max power from solar surplus and grid.
+// Surplus which is not stored is fed in at the feed-in price. Energy left at the end of the horizon is not valued.
+func (t *BatteryPlanner) Plan(bat Battery) (BatteryPlan, error) {
+	if bat.Capacity <= 0 {
+		return nil, errors.New("unknown battery capacity")
+	}
+
+	rates := forecastRates(t.log, t.tariff)
+	if len(rates) == 0 {
+		return nil, errors.New("no grid tariff")
+	}
+
+	feedin := forecastRates(t.log, t.feedin)
+	solar := forecastRates(t.log, t.solar)
+	baseload := forecastRates(t.log, t.baseload)
+
+	capacity := bat.Capacity * 1e3
+	efficiency := bat.Efficiency
+	if efficiency <= 0 || efficiency > 1 {
+		efficiency = DefaultBatteryEfficiency
+	}
+
+	maxCharge := bat.MaxChargePower
+	if maxCharge <= 0 {
+		maxCharge = capacity
+	}
+	maxDischarge := bat.MaxDischargePower
+	if maxDischarge <= 0 {
+		maxDischarge = capacity
+	}
+
+	valueAt := func(rr api.Rates, ts time.Time) float64 {
+		if r, err := rr.At(ts); err == nil {
+			return r.Value
+		}
+		return 0
+	}
+
+	// remaining slots
+	now := t.clock.Now()
+
+	var slots api.Rates
+	for _, r := range rates {
+		if !r.End.After(now) {
+			continue
+		}
+		r.Start = later(r.Start, now)
+		slots = append(slots, r)
+	}
+
+	if len(slots) == 0 {
+		return nil, errors.New("no future grid tariff")
+	}
+
+	// operate the battery in all modes
+	steps := func(k int, energy float64) []batteryStep {
+		slot := slots[k]
+		h := slot.End.Sub(slot.Start).Hours()
+		deficit := valueAt(baseload, slot.Start) - valueAt(solar, slot.Start)
+
+		step := func(mode api.BatteryMode, power float64) batteryStep {
+			if power < 0 {
+				power = max(power, -maxCharge, -(capacity-energy)/(h*efficiency))
+			} else {
+				power = min(power, maxDischarge, energy/h)
+			}
+
+			next := energy - power*h
+			if power < 0 {
+				next = energy - power*h*efficiency
+			}
+
+			grid := deficit - power
+			price := slot.Value
+			if grid < 0 {
+				price = valueAt(feedin, slot.Start)
+			}
+
+			return batteryStep{
+				mode:   mode,
+				energy: min(max(next, 0), capacity),
+				power:  power,
+				grid:   grid,
+				cost:   grid * h / 1e3 * price,
+			}
+		}
+
+		return []batteryStep{
+			step(api.BatteryNormal, deficit),
+			step(api.BatteryHold, min(deficit, 0)),
+			step(api.BatteryCharge, -maxCharge),
+		}
+	}
+
+	// cost-to-go per soc level, interpolated between levels
+	value := make([][]float64, len(slots)+1)
+	value[len(slots)] = make([]float64, batteryLevels+1)
+
+	interpolate := func(v []float64, energy float64) float64 {
+		x := energy / capacity * batteryLevels
+		i := min(int(x), batteryLevels-1)
+		return v[i] + (x-float64(i))*(v[i+1]-v[i])
+	}
+
+	best := func(k int, energy float64) (batteryStep, float64) {
+		var (
+			res  batteryStep
+			cost = math.Inf(1)
+		)
+
+		// prefer normal over hold over charge for equal cost
+		for _, s := range steps(k, energy) {
+			if c := s.cost + interpolate(value[k+1], s.energy); c < cost-1e-9 {
+				res, cost = s, c
+			}
+		}
+
+		return res, cost
+	}
+
+	for k := len(slots) - 1; k >= 0; k-- {
+		value[k] = make([]float64, batteryLevels+1)
+		for i := range value[k] {
+			_, value[k][i] = best(k, float64(i)/batteryLevels*capacity)
+		}
+	}
+
+	// follow the optimal modes from the current soc
+	res := make(BatteryPlan, 0, len(slots))
+	energy := min(max(bat.Soc, 0), 100) / 100 * capacity
+
+	for k, slot := range slots {
+		s, _ := best(k, energy)
+		energy = s.energy
+
+		res = append(res, BatterySlot{
+			Rate:  slot,
+			Mode:  s.mode,
+			Power: s.power,
+			Soc:   energy / capacity * 100,
+			Grid:  s.grid,
+		})
+	}
+
+	return res, nil
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,93 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func batteryPlanner(t *testing.T, clock *clock.Mock, prices, solar, baseload []float64) *BatteryPlanner {
+	ctrl := gomock.NewController(t)
+
+	forecast := func(values []float64) api.Tariff {
+		if values == nil {
+			return nil
+		}
+		trf := api.NewMockTariff(ctrl)
+		trf.EXPECT().Rates().AnyTimes().Return(rates(values, clock.Now(), time.Hour), nil)
+		return trf
+	}
+
+	p := NewBatteryPlanner(util.NewLogger("foo"), forecast(prices), nil, forecast(solar), forecast(baseload))
+	p.clock = clock
+
+	return p
+}
+
+func batteryModes(plan BatteryPlan) []api.BatteryMode {
+	var res []api.BatteryMode
+	for _, slot := range plan {
+		res = append(res, slot.Mode)
+	}
+	return res
+}
+
+func TestBatteryPlanGridCharge(t *testing.T) {
+	clock := clock.NewMock()
+	p := batteryPlanner(t, clock, []float64{0.1, 0.1, 0.4, 0.4}, nil, []float64{1e3, 1e3, 1e3, 1e3})
+
+	plan, err := p.Plan(Battery{Capacity: 2, Efficiency: 1})
+	require.NoError(t, err)
+
+	// charge once in the cheap hours to cover the expensive hours
+	assert.Equal(t, []api.BatteryMode{api.BatteryNormal, api.BatteryCharge, api.BatteryNormal, api.BatteryNormal}, batteryModes(plan))
+	assert.Equal(t, -2e3, plan[1].Power)
+	assert.Equal(t, 3e3, plan[1].Grid)
+	assert.Equal(t, 100.0, plan[1].Soc)
+	assert.Equal(t, 0.0, plan[3].Soc)
+
+	// losses exceed the price difference
+	p = batteryPlanner(t, clock, []float64{0.3, 0.3, 0.32, 0.32}, nil, []float64{1e3, 1e3, 1e3, 1e3})
+
+	plan, err = p.Plan(Battery{Capacity: 2, Efficiency: 0.9})
+	require.NoError(t, err)
+	assert.Equal(t, []api.BatteryMode{api.BatteryNormal, api.BatteryNormal, api.BatteryNormal, api.BatteryNormal}, batteryModes(plan))
+}
+
+func TestBatteryPlanHold(t *testing.T) {
+	clock := clock.NewMock()
+	p := batteryPlanner(t, clock, []float64{0.2, 0.2, 0.4}, nil, []float64{1e3, 1e3, 1e3})
+
+	plan, err := p.Plan(Battery{Capacity: 1, Soc: 100, Efficiency: 0.9})
+	require.NoError(t, err)
+
+	// save the stored energy for the expensive hour
+	assert.Equal(t, []api.BatteryMode{api.BatteryHold, api.BatteryHold, api.BatteryNormal}, batteryModes(plan))
+	assert.Equal(t, 1e3, plan[2].Power)
+}
+
+func TestBatteryPlanSolar(t *testing.T) {
+	clock := clock.NewMock()
+	p := batteryPlanner(t, clock, []float64{0.1, 0.3, 0.3}, []float64{3e3, 0, 0}, []float64{1e3, 1e3, 1e3})
+
+	plan, err := p.Plan(Battery{Capacity: 2, Efficiency: 1})
+	require.NoError(t, err)
+
+	// solar surplus fills the battery without grid charging
+	assert.Equal(t, []api.BatteryMode{api.BatteryNormal, api.BatteryNormal, api.BatteryNormal}, batteryModes(plan))
+	assert.Equal(t, 0.0, plan[0].Grid)
+	assert.Equal(t, 100.0, plan[0].Soc)
+}
+
+func TestBatteryPlanNoTariff(t *testing.T) {
+	p := batteryPlanner(t, clock.NewMock(), nil, nil, nil)
+
+	_, err := p.Plan(Battery{Capacity: 2})
+	assert.Error(t, err)
+}
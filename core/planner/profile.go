@@ -0,0 +1,62 @@
+package planner
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// PowerSlot is a plan slot with its planned charge power
+type PowerSlot struct {
+	api.Rate
+	Power float64 `json:"power"` // W planned charge power
+	Solar float64 `json:"solar"` // W of the planned power expected from solar surplus
+}
+
+// PowerPlan is a charging plan with per-slot power
+type PowerPlan []PowerSlot
+
+// Rates returns the plan's slots
+func (p PowerPlan) Rates() api.Rates {
+	if len(p) == 0 {
+		return nil
+	}
+
+	res := make(api.Rates, 0, len(p))
+	for _, slot := range p {
+		res = append(res, slot.Rate)
+	}
+	return res
+}
+
+// Energy returns the planned energy in Wh
+func (p PowerPlan) Energy() float64 {
+	var res float64
+	for _, slot := range p {
+		res += slot.Power * slot.End.Sub(slot.Start).Hours()
+	}
+	return res
+}
+
+// SlotAt returns the slot for the given time or an empty slot
+func (p PowerPlan) SlotAt(ts time.Time) PowerSlot {
+	for _, slot := range p {
+		if !slot.Start.After(ts) && slot.End.After(ts) {
+			return slot
+		}
+	}
+	return PowerSlot{}
+}
+
+// NewPowerPlan converts a plan at constant power
+func NewPowerPlan(plan api.Rates, power float64) PowerPlan {
+	if len(plan) == 0 {
+		return nil
+	}
+
+	res := make(PowerPlan, 0, len(plan))
+	for _, slot := range plan {
+		res = append(res, PowerSlot{Rate: slot, Power: power})
+	}
+	return res
+}
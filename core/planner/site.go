@@ -0,0 +1,421 @@
+package planner
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Request is the charging plan demand of a single loadpoint
+type Request struct {
+	Priority         int           // higher priority is served first
+	TargetTime       time.Time     // plan goal time
+	RequiredDuration time.Duration // charging duration at max power
+	Precondition     time.Duration // preferred charging duration before target time
+	MaxPower         float64       // W
+	MinPower         float64       // W, slots with less available power are not used
+}
+
+// Forecast provides power forecast rates in W
+type Forecast interface {
+	Rates() (api.Rates, error)
+}
+
+// minEnergy is the remaining energy in Wh below which a request is considered planned
+const minEnergy = 1e-3
+
+// energy returns the required energy in Wh
+func (r Request) energy() float64 {
+	return r.MaxPower * r.RequiredDuration.Hours()
+}
+
+// allocation is the power assigned to a plan slot
+type allocation struct {
+	Start, End time.Time
+	Power      float64 // total power
+	Solar      float64 // power from solar surplus
+	Request    int     // index of the request
+}
+
+// segment is a slot of constant price, solar surplus and available power
+type segment struct {
+	api.Rate
+	Power     float64 // available power
+	Own       float64 // power already allocated to the request
+	Solar     float64 // available solar surplus
+	Preferred bool    // precondition or beyond tariff horizon
+}
+
+// SitePlanner plans the charging plans of multiple loadpoints jointly, sharing the site's max power and solar surplus
+type SitePlanner struct {
+	log      *util.Logger
+	clock    clock.Clock // mockable time
+	tariff   api.Tariff
+	solar    api.Tariff // solar forecast in W
+	baseload Forecast   // expected household consumption in W
+	planner  *Planner
+}
+
+// NewSitePlanner creates a site planner
+func NewSitePlanner(log *util.Logger, tariff, solar api.Tariff, opt ...func(t *SitePlanner)) *SitePlanner {
+	p := &SitePlanner{
+		log:    log,
+		clock:  clock.New(),
+		tariff: tariff,
+		solar:  solar,
+	}
+
+	for _, o := range opt {
+		o(p)
+	}
+
+	p.planner = New(log, tariff, func(t *Planner) {
+		t.clock = p.clock
+	})
+
+	return p
+}
+
+// WithBaseload sets the expected household consumption which is subtracted from the solar forecast
+func WithBaseload(baseload Forecast) func(t *SitePlanner) {
+	return func(t *SitePlanner) {
+		t.baseload = baseload
+	}
+}
+
+// Plan creates the plans of all requests, index-aligned with the requests.
+// The plans are sorted by time. See PlanPower.
+func (t *SitePlanner) Plan(reqs []Request, maxPower float64) []api.Rates {
+	res := make([]api.Rates, len(reqs))
+	for i, p := range t.PlanPower(reqs, maxPower) {
+		res[i] = p.Rates()
+	}
+	return res
+}
+
+// PlanPower creates the power plans of all requests, index-aligned with the requests.
+// Requests are served by descending priority and ascending target time. Each request first receives the
+// expected solar surplus, then the lowest-cost slots that are left after serving all preceding requests
+// within max power (W, 0 = unlimited). The last slot is filled at reduced power down to the request's min power.
+// Without solar forecast and as long as all requests fit within max power, each plan is the single loadpoint plan.
+func (t *SitePlanner) PlanPower(reqs []Request, maxPower float64) []PowerPlan {
+	res := make([]PowerPlan, len(reqs))
+
+	if maxPower <= 0 {
+		maxPower = math.Inf(1)
+	}
+
+	solar := t.surplus()
+
+	// plans don't compete for power
+	var total float64
+	for _, r := range reqs {
+		total += r.MaxPower
+	}
+
+	if total <= maxPower && len(solar) == 0 {
+		for i, r := range reqs {
+			res[i] = NewPowerPlan(t.planner.Plan(r.RequiredDuration, r.Precondition, r.TargetTime), r.MaxPower)
+		}
+		return res
+	}
+
+	rates := t.rates(t.tariff)
+
+	order := make([]int, len(reqs))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Or(
+			cmp.Compare(reqs[j].Priority, reqs[i].Priority),
+			reqs[i].TargetTime.Compare(reqs[j].TargetTime),
+		)
+	})
+
+	var allocs []allocation
+	for _, i := range order {
+		allocs = t.plan(i, reqs[i], rates, solar, allocs, maxPower)
+		res[i] = profile(i, allocs, rates)
+	}
+
+	return res
+}
+
+// surplus returns the expected solar surplus, i.e. the solar forecast reduced by the household baseload
+func (t *SitePlanner) surplus() api.Rates {
+	solar := t.rates(t.solar)
+	if len(solar) == 0 || t.baseload == nil {
+		return solar
+	}
+
+	baseload := t.rates(t.baseload)
+
+	for i, r := range solar {
+		if b, err := baseload.At(r.Start); err == nil {
+			solar[i].Value = max(r.Value-b.Value, 0)
+		}
+	}
+
+	return solar
+}
+
+// rates returns the forecast's rates sorted by time
+func (t *SitePlanner) rates(tariff Forecast) api.Rates {
+	return forecastRates(t.log, tariff)
+}
+
+// forecastRates returns a sorted copy of the forecast's rates
+func forecastRates(log *util.Logger, tariff Forecast) api.Rates {
+	if tariff == nil {
+		return nil
+	}
+
+	rates, err := tariff.Rates()
+	if err != nil {
+		log.DEBUG.Printf("plan: %v", err)
+		return nil
+	}
+
+	rates = slices.Clone(rates)
+	rates.Sort()
+
+	return rates
+}
+
+// plan allocates the lowest-cost power for a single request from the power left by previous allocations
+func (t *SitePlanner) plan(id int, req Request, rates, solar api.Rates, allocs []allocation, maxPower float64) []allocation {
+	if req.RequiredDuration <= 0 || req.MaxPower <= 0 {
+		return allocs
+	}
+
+	now := t.clock.Now()
+	energy := req.energy()
+
+	targetTime := req.TargetTime
+	if targetTime.Before(now) {
+		targetTime = now
+	}
+
+	// fill takes the segment's power (or less, if the remaining energy permits) and returns the allocation
+	fill := func(s segment, power float64, lateStart bool) allocation {
+		a := allocation{Start: s.Start, End: s.End, Power: power, Request: id}
+
+		// slot covers more than we need, so reduce power or shorten it
+		if slotEnergy := power * s.End.Sub(s.Start).Hours(); slotEnergy > energy {
+			a.Power = max(energy/s.End.Sub(s.Start).Hours(), req.MinPower-s.Own)
+			d := time.Duration(energy / a.Power * float64(time.Hour))
+
+			if lateStart {
+				a.Start = a.End.Add(-d)
+			} else {
+				a.End = a.Start.Add(d)
+			}
+		}
+
+		energy -= a.Power * a.End.Sub(a.Start).Hours()
+
+		return a
+	}
+
+	// expected solar surplus, largest first
+	if len(solar) > 0 {
+		segs := t.segments(id, req, rates, solar, allocs, maxPower, now, targetTime)
+		segs = slices.DeleteFunc(segs, func(s segment) bool {
+			return s.Solar <= 0 || s.Own+min(s.Solar, s.Power) < req.MinPower
+		})
+
+		slices.SortStableFunc(segs, func(i, j segment) int {
+			return cmp.Or(cmp.Compare(min(j.Solar, j.Power), min(i.Solar, i.Power)), j.Start.Compare(i.Start))
+		})
+
+		for _, s := range segs {
+			if energy < minEnergy {
+				break
+			}
+
+			a := fill(s, min(s.Solar, s.Power), true)
+			a.Solar = a.Power
+			allocs = append(allocs, a)
+		}
+	}
+
+	// lowest cost grid power
+	if energy >= minEnergy {
+		segs := t.segments(id, req, rates, solar, allocs, maxPower, now, targetTime)
+
+		// prefer precondition slots, then lowest cost, then late slots
+		slices.SortStableFunc(segs, func(i, j segment) int {
+			switch {
+			case i.Preferred && !j.Preferred:
+				return -1
+			case !i.Preferred && j.Preferred:
+				return +1
+			default:
+				return sortByCost(i.Rate, j.Rate)
+			}
+		})
+
+		var plan api.Rates
+		for _, s := range segs {
+			if energy < minEnergy {
+				break
+			}
+
+			// the first (if not single) slot should start as late as possible
+			a := fill(s, s.Power, IsFirst(s.Rate, plan) && len(plan) > 0)
+			plan = append(plan, s.Rate)
+			allocs = append(allocs, a)
+		}
+	}
+
+	// target time can not be met, continue charging after target time as soon as possible
+	if energy >= minEnergy {
+		horizon := targetTime
+		for _, a := range allocs {
+			if a.End.After(horizon) {
+				horizon = a.End
+			}
+		}
+		horizon = horizon.Add(time.Duration(energy / min(req.MaxPower, maxPower) * float64(time.Hour)))
+
+		for _, s := range t.segments(id, req, rates, solar, allocs, maxPower, targetTime, horizon) {
+			if energy < minEnergy {
+				break
+			}
+
+			allocs = append(allocs, fill(s, s.Power, false))
+		}
+	}
+
+	return allocs
+}
+
+// segments splits the time between start and end into slots of constant price, solar surplus and available power.
+// Slots with less than the request's min power available are omitted. Segments are sorted by time.
+func (t *SitePlanner) segments(id int, req Request, rates, solar api.Rates, allocs []allocation, maxPower float64, start, end time.Time) []segment {
+	bounds := []time.Time{start, end}
+
+	add := func(ts time.Time) {
+		if ts.After(start) && ts.Before(end) {
+			bounds = append(bounds, ts)
+		}
+	}
+
+	for _, r := range slices.Concat(rates, solar) {
+		add(r.Start)
+		add(r.End)
+	}
+
+	for _, a := range allocs {
+		add(a.Start)
+		add(a.End)
+	}
+
+	preCondStart := req.TargetTime.Add(-req.Precondition)
+	if req.Precondition > 0 {
+		add(preCondStart)
+	}
+
+	slices.SortFunc(bounds, time.Time.Compare)
+	bounds = slices.CompactFunc(bounds, time.Time.Equal)
+
+	var res []segment
+
+	for i := 1; i < len(bounds); i++ {
+		from, to := bounds[i-1], bounds[i]
+
+		var used, own, solarUsed float64
+		for _, a := range allocs {
+			if a.Start.Before(to) && a.End.After(from) {
+				used += a.Power
+				solarUsed += a.Solar
+				if a.Request == id {
+					own += a.Power
+				}
+			}
+		}
+
+		power := min(req.MaxPower-own, maxPower-used)
+		if power <= 0 || own+power < req.MinPower {
+			continue
+		}
+
+		s := segment{
+			Rate:  api.Rate{Start: from, End: to},
+			Power: power,
+			Own:   own,
+		}
+
+		if r, err := solar.At(from); err == nil {
+			s.Solar = max(r.Value-solarUsed, 0)
+		}
+
+		if r, err := rates.At(from); err == nil {
+			s.Value = r.Value
+		} else {
+			// charge beyond the tariff horizon like the single loadpoint planner
+			s.Preferred = true
+		}
+
+		if req.Precondition > 0 && !from.Before(preCondStart) {
+			s.Preferred = true
+		}
+
+		res = append(res, s)
+	}
+
+	return res
+}
+
+// profile returns the power plan of the given request from all allocations
+func profile(id int, allocs []allocation, rates api.Rates) PowerPlan {
+	var bounds []time.Time
+	for _, a := range allocs {
+		if a.Request == id {
+			bounds = append(bounds, a.Start, a.End)
+		}
+	}
+
+	slices.SortFunc(bounds, time.Time.Compare)
+	bounds = slices.CompactFunc(bounds, time.Time.Equal)
+
+	var res PowerPlan
+
+	for i := 1; i < len(bounds); i++ {
+		slot := PowerSlot{Rate: api.Rate{Start: bounds[i-1], End: bounds[i]}}
+
+		for _, a := range allocs {
+			if a.Request == id && a.Start.Before(slot.End) && a.End.After(slot.Start) {
+				slot.Power += a.Power
+				slot.Solar += a.Solar
+			}
+		}
+
+		if slot.Power <= 0 {
+			continue
+		}
+
+		if r, err := rates.At(slot.Start); err == nil {
+			slot.Value = r.Value
+		}
+
+		// join adjacent slots of equal cost and power
+		if n := len(res); n > 0 {
+			if prev := res[n-1]; prev.End.Equal(slot.Start) && prev.Value == slot.Value && prev.Power == slot.Power && prev.Solar == slot.Solar {
+				res[n-1].End = slot.End
+				continue
+			}
+		}
+
+		res = append(res, slot)
+	}
+
+	return res
+}
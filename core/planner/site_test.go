@@ -0,0 +1,209 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func sitePlanner(t *testing.T, clock *clock.Mock, prices []float64) *SitePlanner {
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+	trf.EXPECT().Rates().AnyTimes().Return(rates(prices, clock.Now(), time.Hour), nil)
+
+	return NewSitePlanner(util.NewLogger("foo"), trf, nil, func(t *SitePlanner) {
+		t.clock = clock
+	})
+}
+
+// sitePower returns the max combined power of all plans
+func sitePower(plans []api.Rates, reqs []Request) float64 {
+	var res float64
+	for _, p := range plans {
+		for _, slot := range p {
+			var power float64
+			for i, other := range plans {
+				if !SlotAt(slot.Start, other).End.IsZero() {
+					power += reqs[i].MaxPower
+				}
+			}
+			res = max(res, power)
+		}
+	}
+	return res
+}
+
+func TestSitePlanSharedSlots(t *testing.T) {
+	clock := clock.NewMock()
+	p := sitePlanner(t, clock, []float64{20, 60, 10, 80, 40, 90})
+
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: 2 * time.Hour, MaxPower: 11000},
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: 2 * time.Hour, MaxPower: 11000},
+	}
+
+	// independent plans without power limit
+	plans := p.Plan(reqs, 0)
+	assert.Equal(t, plans[0], plans[1])
+	assert.Equal(t, p.planner.Plan(2*time.Hour, 0, reqs[0].TargetTime), plans[0])
+
+	// shared power limit
+	plans = p.Plan(reqs, 11000)
+	assert.Equal(t, 2*time.Hour, Duration(plans[0]))
+	assert.Equal(t, 2*time.Hour, Duration(plans[1]))
+	assert.Equal(t, 15.0, AverageCost(plans[0]))
+	assert.Equal(t, 50.0, AverageCost(plans[1]))
+	assert.Equal(t, 11000.0, sitePower(plans, reqs))
+}
+
+func TestSitePlanPriority(t *testing.T) {
+	clock := clock.NewMock()
+	p := sitePlanner(t, clock, []float64{20, 60, 10, 80, 40, 90})
+
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 11000},
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 11000, Priority: 1},
+	}
+
+	plans := p.Plan(reqs, 11000)
+	assert.Equal(t, 20.0, AverageCost(plans[0]))
+	assert.Equal(t, 10.0, AverageCost(plans[1]), "higher priority gets cheapest slot")
+}
+
+func TestSitePlanDeadline(t *testing.T) {
+	clock := clock.NewMock()
+	p := sitePlanner(t, clock, []float64{20, 60, 10, 80, 40, 90})
+
+	// earlier target is planned first
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 11000},
+		{TargetTime: clock.Now().Add(3 * time.Hour), RequiredDuration: 2 * time.Hour, MaxPower: 11000},
+	}
+
+	plans := p.Plan(reqs, 11000)
+	assert.Equal(t, api.Rates{
+		{Start: clock.Now(), End: clock.Now().Add(time.Hour), Value: 20},
+		{Start: clock.Now().Add(2 * time.Hour), End: clock.Now().Add(3 * time.Hour), Value: 10},
+	}, plans[1])
+	assert.Equal(t, api.Rates{
+		{Start: clock.Now().Add(4 * time.Hour), End: clock.Now().Add(5 * time.Hour), Value: 40},
+	}, plans[0])
+}
+
+func TestSitePlanPartialPower(t *testing.T) {
+	clock := clock.NewMock()
+	p := sitePlanner(t, clock, []float64{20, 60, 10, 80, 40, 90})
+
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 8000, Priority: 1},
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 8000, MinPower: 1400},
+	}
+
+	// remaining 3 kW in the cheapest slot, rest at reduced power in the next cheapest
+	plans := p.PlanPower(reqs, 11000)
+	assert.Equal(t, PowerPlan{
+		{Rate: api.Rate{Start: clock.Now(), End: clock.Now().Add(time.Hour), Value: 20}, Power: 5000},
+		{Rate: api.Rate{Start: clock.Now().Add(2 * time.Hour), End: clock.Now().Add(3 * time.Hour), Value: 10}, Power: 3000},
+	}, plans[1])
+	assert.Equal(t, 8000.0, plans[1].Energy())
+
+	// min power not available in the cheapest slot
+	reqs[1].MinPower = 6000
+	plans = p.PlanPower(reqs, 11000)
+	assert.Equal(t, PowerPlan{
+		{Rate: api.Rate{Start: clock.Now(), End: clock.Now().Add(time.Hour), Value: 20}, Power: 8000},
+	}, plans[1])
+
+	// reduced power below min power shortens the slot
+	reqs[1].RequiredDuration = 30 * time.Minute
+	plans = p.PlanPower(reqs, 11000)
+	assert.Equal(t, PowerPlan{
+		{Rate: api.Rate{Start: clock.Now(), End: clock.Now().Add(40 * time.Minute), Value: 20}, Power: 6000},
+	}, plans[1])
+}
+
+func TestSitePlanSolar(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+	trf.EXPECT().Rates().AnyTimes().Return(rates([]float64{20, 60, 10, 80, 40, 90}, clock.Now(), time.Hour), nil)
+
+	solar := api.NewMockTariff(ctrl)
+	solar.EXPECT().Rates().AnyTimes().Return(rates([]float64{0, 1000, 4000, 6000, 0, 0}, clock.Now(), time.Hour), nil)
+
+	p := NewSitePlanner(util.NewLogger("foo"), trf, solar, func(t *SitePlanner) {
+		t.clock = clock
+	})
+
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 11000, MinPower: 1400},
+	}
+
+	// solar surplus first, remaining energy from the cheapest slot, topping up solar
+	plans := p.PlanPower(reqs, 0)
+	assert.Equal(t, PowerPlan{
+		{Rate: api.Rate{Start: clock.Now().Add(2 * time.Hour), End: clock.Now().Add(3 * time.Hour), Value: 10}, Power: 5000, Solar: 4000},
+		{Rate: api.Rate{Start: clock.Now().Add(3 * time.Hour), End: clock.Now().Add(4 * time.Hour), Value: 80}, Power: 6000, Solar: 6000},
+	}, plans[0])
+	assert.Equal(t, 11000.0, plans[0].Energy())
+
+	// solar surplus is shared
+	reqs = append(reqs, Request{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 3000, MinPower: 1400})
+	plans = p.PlanPower(reqs, 0)
+	assert.Equal(t, PowerPlan{
+		{Rate: api.Rate{Start: clock.Now().Add(2 * time.Hour), End: clock.Now().Add(3 * time.Hour), Value: 10}, Power: 3000},
+	}, plans[1])
+}
+
+func TestSitePlanSolarBaseload(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+	trf.EXPECT().Rates().AnyTimes().Return(rates([]float64{20, 60, 10, 80, 40, 90}, clock.Now(), time.Hour), nil)
+
+	solar := api.NewMockTariff(ctrl)
+	solar.EXPECT().Rates().AnyTimes().Return(rates([]float64{0, 1000, 4000, 6000, 0, 0}, clock.Now(), time.Hour), nil)
+
+	baseload := api.NewMockTariff(ctrl)
+	baseload.EXPECT().Rates().AnyTimes().Return(rates([]float64{1000, 1000, 1000, 1000, 1000, 1000}, clock.Now(), time.Hour), nil)
+
+	p := NewSitePlanner(util.NewLogger("foo"), trf, solar, WithBaseload(baseload), func(t *SitePlanner) {
+		t.clock = clock
+	})
+
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(6 * time.Hour), RequiredDuration: time.Hour, MaxPower: 11000, MinPower: 1400},
+	}
+
+	// only the surplus above baseload is planned as solar, the remainder is bought from the grid
+	plans := p.PlanPower(reqs, 0)
+	assert.Equal(t, PowerPlan{
+		{Rate: api.Rate{Start: clock.Now().Add(2 * time.Hour), End: clock.Now().Add(3 * time.Hour), Value: 10}, Power: 6000, Solar: 3000},
+		{Rate: api.Rate{Start: clock.Now().Add(3 * time.Hour), End: clock.Now().Add(4 * time.Hour), Value: 80}, Power: 5000, Solar: 5000},
+	}, plans[0])
+}
+
+func TestSitePlanOverrun(t *testing.T) {
+	clock := clock.NewMock()
+	p := NewSitePlanner(util.NewLogger("foo"), nil, nil, func(t *SitePlanner) {
+		t.clock = clock
+	})
+
+	reqs := []Request{
+		{TargetTime: clock.Now().Add(2 * time.Hour), RequiredDuration: 2 * time.Hour, MaxPower: 11000, Priority: 1},
+		{TargetTime: clock.Now().Add(2 * time.Hour), RequiredDuration: time.Hour, MaxPower: 11000},
+	}
+
+	// lower priority charges after the higher priority plan
+	plans := p.Plan(reqs, 11000)
+	assert.Equal(t, api.Rates{{Start: clock.Now(), End: clock.Now().Add(2 * time.Hour)}}, plans[0])
+	assert.Equal(t, api.Rates{{Start: clock.Now().Add(2 * time.Hour), End: clock.Now().Add(3 * time.Hour)}}, plans[1])
+}
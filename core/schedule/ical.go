@@ -0,0 +1,366 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// event is a calendar event
+type event struct {
+	UID          string
+	Start        time.Time
+	Summary      string
+	Rule         *recurrence // RRULE
+	Dates        []time.Time // RDATE
+	Exceptions   []time.Time // EXDATE and RECURRENCE-ID of modified occurrences
+	RecurrenceID time.Time   // modified occurrence of a recurring event
+	Err          error       // unsupported recurrence
+}
+
+// occurrences returns the event's start times in (from, to]
+func (ev event) occurrences(from, to time.Time) []time.Time {
+	times := slices.Clone(ev.Dates)
+	if ev.Rule != nil {
+		times = append(times, ev.Rule.occurrences(ev.Start, from, to)...)
+	} else {
+		times = append(times, ev.Start)
+	}
+
+	var res []time.Time
+	for _, ts := range times {
+		if ts.After(from) && !ts.After(to) && !slices.ContainsFunc(ev.Exceptions, ts.Equal) {
+			res = append(res, ts)
+		}
+	}
+
+	return res
+}
+
+// parseCalendar returns the events of an iCal (RFC 5545) calendar
+func parseCalendar(data string) ([]event, error) {
+	// unfold continuation lines
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var (
+		res     []event
+		current *event
+	)
+
+	for _, line := range strings.Split(data, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		name, params, _ := strings.Cut(name, ";")
+
+		if current == nil {
+			if strings.EqualFold(name, "BEGIN") && strings.EqualFold(value, "VEVENT") {
+				current = new(event)
+			}
+			continue
+		}
+
+		var err error
+
+		switch strings.ToUpper(name) {
+		case "END":
+			if strings.EqualFold(value, "VEVENT") {
+				if current.Start.IsZero() {
+					return nil, errors.New("event without start")
+				}
+				res = append(res, *current)
+				current = nil
+			}
+
+		case "UID":
+			current.UID = value
+
+		case "DTSTART":
+			current.Start, err = parseDateTime(params, value)
+
+		case "SUMMARY":
+			current.Summary = unescapeText(value)
+
+		case "RRULE":
+			if current.Rule, err = parseRecurrence(value); err != nil {
+				current.Err, err = err, nil
+			}
+
+		case "RDATE":
+			var ts []time.Time
+			if ts, err = parseDateTimes(params, value); err != nil {
+				current.Err, err = err, nil
+			}
+			current.Dates = append(current.Dates, ts...)
+
+		case "EXDATE":
+			var ts []time.Time
+			ts, err = parseDateTimes(params, value)
+			current.Exceptions = append(current.Exceptions, ts...)
+
+		case "RECURRENCE-ID":
+			current.RecurrenceID, err = parseDateTime(params, value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.ToLower(name), err)
+		}
+	}
+
+	// modified occurrences replace the original occurrence of the recurring event
+	for _, ev := range res {
+		if ev.RecurrenceID.IsZero() {
+			continue
+		}
+		for i := range res {
+			if res[i].UID == ev.UID && res[i].RecurrenceID.IsZero() {
+				res[i].Exceptions = append(res[i].Exceptions, ev.RecurrenceID)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// recurrence is an event's recurrence rule. Only daily and weekly frequencies are supported.
+type recurrence struct {
+	Freq      string // DAILY or WEEKLY
+	Interval  int
+	Count     int
+	Until     time.Time
+	Weekdays  []time.Weekday
+	WeekStart time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRecurrence parses an RRULE value
+func parseRecurrence(value string) (*recurrence, error) {
+	res := recurrence{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+
+		var err error
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			res.Freq = strings.ToUpper(val)
+			if res.Freq != "DAILY" && res.Freq != "WEEKLY" {
+				return nil, fmt.Errorf("unsupported frequency: %s", val)
+			}
+		case "INTERVAL":
+			if res.Interval, err = strconv.Atoi(val); err == nil && res.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			res.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			if res.Until, err = parseDateTime("", val); err == nil && len(val) == len("20060102") {
+				// inclusive
+				res.Until = res.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				wd, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported weekday: %s", day)
+				}
+				res.Weekdays = append(res.Weekdays, wd)
+			}
+		case "WKST":
+			var ok bool
+			if res.WeekStart, ok = weekdays[strings.ToUpper(val)]; !ok {
+				err = errors.New("invalid weekday")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule: %s", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.ToLower(key), err)
+		}
+	}
+
+	if res.Freq == "" {
+		return nil, errors.New("missing frequency")
+	}
+
+	return &res, nil
+}
+
+// occurrences returns the rule's occurrences of an event starting at start in (from, to]
+func (r recurrence) occurrences(start, from, to time.Time) []time.Time {
+	// days per period and period length
+	days, length := r.Interval, 1
+	base := start
+
+	if r.Freq == "WEEKLY" {
+		days, length = 7*r.Interval, 7
+		// start of week containing start
+		base = start.AddDate(0, 0, -(int(start.Weekday()-r.WeekStart)+7)%7)
+	}
+
+	weekdays := r.Weekdays
+	if len(weekdays) == 0 && r.Freq == "WEEKLY" {
+		weekdays = []time.Weekday{start.Weekday()}
+	}
+
+	// skip periods before from unless occurrences are counted, keeping a period for daylight saving changes
+	var period int
+	if r.Count == 0 && from.After(base) {
+		period = max(0, int(from.Sub(base).Hours()/24)/days-1)
+	}
+
+	var (
+		res   []time.Time
+		count int
+	)
+
+	for ; !base.AddDate(0, 0, period*days).After(to); period++ {
+		for day := range length {
+			ts := base.AddDate(0, 0, period*days+day)
+			if ts.Before(start) || len(weekdays) > 0 && !slices.Contains(weekdays, ts.Weekday()) {
+				continue
+			}
+
+			if count++; r.Count > 0 && count > r.Count || !r.Until.IsZero() && ts.After(r.Until) {
+				return res
+			}
+
+			if ts.After(from) && !ts.After(to) {
+				res = append(res, ts)
+			}
+		}
+	}
+
+	return res
+}
+
+// parseDateTime parses an iCal date or date-time value with optional TZID parameter
+func parseDateTime(params, value string) (time.Time, error) {
+	loc := time.Local
+
+	for _, param := range strings.Split(params, ";") {
+		if k, v, ok := strings.Cut(param, "="); ok && strings.EqualFold(k, "TZID") {
+			var err error
+			if loc, err = time.LoadLocation(strings.Trim(v, `"`)); err != nil {
+				return time.Time{}, err
+			}
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	case len(value) == len("20060102"):
+		return time.ParseInLocation("20060102", value, loc)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+// parseDateTimes parses a comma-separated list of iCal date or date-time values
+func parseDateTimes(params, value string) ([]time.Time, error) {
+	if strings.Contains(strings.ToUpper(params), "VALUE=PERIOD") {
+		return nil, errors.New("periods are not supported")
+	}
+
+	var res []time.Time
+	for _, v := range strings.Split(value, ",") {
+		ts, err := parseDateTime(params, v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ts)
+	}
+
+	return res, nil
+}
+
+// unescapeText removes iCal text escaping
+func unescapeText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// parseAction parses key=value settings like "mode=pv limitsoc=80" from s into the action.
+// Words that are not settings are ignored.
+func parseAction(s string, action *Action) error {
+	var found bool
+
+	for _, token := range strings.Fields(s) {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			continue
+		}
+
+		if err := setAction(action, strings.ToLower(key), value); err != nil {
+			return err
+		}
+
+		found = true
+	}
+
+	if !found {
+		return errors.New("no settings")
+	}
+
+	return action.Validate()
+}
+
+func setAction(action *Action, key, value string) error {
+	var err error
+
+	switch key {
+	case "loadpoint":
+		action.Loadpoint, err = strconv.Atoi(value)
+	case "mode":
+		action.Mode, err = api.ChargeModeString(value)
+	case "limitsoc":
+		action.LimitSoc, err = parsePtr(value, strconv.Atoi)
+	case "maxcurrent":
+		action.MaxCurrent, err = parsePtr(value, parseFloat)
+	case "priority":
+		action.Priority, err = parsePtr(value, strconv.Atoi)
+	case "buffersoc":
+		action.BufferSoc, err = parsePtr(value, parseFloat)
+	case "bufferstartsoc":
+		action.BufferStartSoc, err = parsePtr(value, parseFloat)
+	case "prioritysoc":
+		action.PrioritySoc, err = parsePtr(value, parseFloat)
+	case "batterydischargecontrol":
+		action.BatteryDischargeControl, err = parsePtr(value, strconv.ParseBool)
+	default:
+		return fmt.Errorf("unknown setting: %s", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+func parsePtr[T any](s string, parse func(string) (T, error)) (*T, error) {
+	v, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
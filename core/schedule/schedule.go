@@ -0,0 +1,293 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// Action is the set of settings applied when a schedule triggers
+type Action struct {
+	Loadpoint  int            `json:"loadpoint,omitempty"` // loadpoint id (1..n), 0 for all loadpoints
+	Mode       api.ChargeMode `json:"mode,omitempty"`
+	LimitSoc   *int           `json:"limitSoc,omitempty"`
+	MaxCurrent *float64       `json:"maxCurrent,omitempty"`
+	Priority   *int           `json:"priority,omitempty"`
+
+	// battery settings
+	BufferSoc               *float64 `json:"bufferSoc,omitempty"`
+	BufferStartSoc          *float64 `json:"bufferStartSoc,omitempty"`
+	PrioritySoc             *float64 `json:"prioritySoc,omitempty"`
+	BatteryDischargeControl *bool    `json:"batteryDischargeControl,omitempty"`
+}
+
+// Validate checks the action's settings
+func (a Action) Validate() error {
+	if a.Loadpoint < 0 {
+		return fmt.Errorf("invalid loadpoint: %d", a.Loadpoint)
+	}
+	if a.Mode != api.ModeEmpty {
+		if _, err := api.ChargeModeString(string(a.Mode)); err != nil {
+			return err
+		}
+	}
+	if a.LimitSoc != nil && (*a.LimitSoc < 0 || *a.LimitSoc > 100) {
+		return fmt.Errorf("limit soc out of range: %d", *a.LimitSoc)
+	}
+	return nil
+}
+
+// Rule is a repeating schedule triggering on the given weekdays and time of day
+type Rule struct {
+	Weekdays []int  `json:"weekdays"` // 0-6 (Sunday-Saturday)
+	Time     string `json:"time"`     // HH:MM
+	Tz       string `json:"tz"`       // timezone in IANA format, local timezone if empty
+	Active   bool   `json:"active"`   // active flag
+	Action
+}
+
+// Validate checks the rule's trigger and action
+func (r Rule) Validate() error {
+	for _, day := range r.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("weekday out of range: %v", day)
+		}
+	}
+	if _, err := r.location(); err != nil {
+		return fmt.Errorf("invalid timezone: %v", err)
+	}
+	if _, err := time.Parse("15:04", r.Time); err != nil {
+		return fmt.Errorf("invalid time: %v", err)
+	}
+	return r.Action.Validate()
+}
+
+// location returns the rule's timezone, defaulting to the local timezone
+func (r Rule) location() (*time.Location, error) {
+	if r.Tz == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(r.Tz)
+}
+
+// occurrences returns the rule's trigger times in (from, to]
+func (r Rule) occurrences(from, to time.Time) []time.Time {
+	loc, err := r.location()
+	if err != nil {
+		return nil
+	}
+
+	tod, err := time.Parse("15:04", r.Time)
+	if err != nil {
+		return nil
+	}
+
+	var res []time.Time
+
+	from = from.In(loc)
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); !day.After(to); day = day.AddDate(0, 0, 1) {
+		ts := time.Date(day.Year(), day.Month(), day.Day(), tod.Hour(), tod.Minute(), 0, 0, loc)
+		if ts.After(from) && !ts.After(to) && slices.Contains(r.Weekdays, int(ts.Weekday())) {
+			res = append(res, ts)
+		}
+	}
+
+	return res
+}
+
+// Calendar is an iCal feed whose events trigger the actions given in their summary
+type Calendar struct {
+	Source    string `json:"source"`              // local .ics file or http(s) url
+	Loadpoint int    `json:"loadpoint,omitempty"` // default loadpoint id (1..n), 0 for all loadpoints
+	Active    bool   `json:"active"`              // active flag
+}
+
+// Config is the persisted scheduler configuration
+type Config struct {
+	Rules     []Rule     `json:"rules"`
+	Calendars []Calendar `json:"calendars"`
+}
+
+// Validate checks all rules and calendars
+func (c Config) Validate() error {
+	for i, r := range c.Rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	for i, cal := range c.Calendars {
+		if cal.Source == "" {
+			return fmt.Errorf("calendar %d: missing source", i+1)
+		}
+		if cal.Loadpoint < 0 {
+			return fmt.Errorf("calendar %d: invalid loadpoint: %d", i+1, cal.Loadpoint)
+		}
+	}
+	return nil
+}
+
+// Trigger is a scheduled action at a point in time
+type Trigger struct {
+	Time time.Time `json:"time"`
+	Action
+}
+
+// calendarEvent is a calendar event triggering its action at each occurrence
+type calendarEvent struct {
+	event
+	Action
+}
+
+// Scheduler determines the actions triggered by rules and calendar events
+type Scheduler struct {
+	mu     sync.RWMutex
+	log    *util.Logger
+	config Config
+	events map[string][]calendarEvent // calendar events by source
+
+	unsupported map[string]bool // events with unsupported recurrence already logged, only used by Refresh
+}
+
+// New creates a scheduler
+func New(log *util.Logger) *Scheduler {
+	return &Scheduler{
+		log:         log,
+		events:      make(map[string][]calendarEvent),
+		unsupported: make(map[string]bool),
+	}
+}
+
+// Config returns the scheduler configuration
+func (s *Scheduler) Config() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// SetConfig validates and applies the scheduler configuration. Calendars are loaded on Refresh.
+func (s *Scheduler) SetConfig(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.config = config
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Refresh reloads all active calendars
+func (s *Scheduler) Refresh() error {
+	calendars := s.Config().Calendars
+
+	events := make(map[string][]calendarEvent)
+	var errs []error
+
+	for _, cal := range calendars {
+		if !cal.Active {
+			continue
+		}
+
+		res, err := s.load(cal)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("calendar %s: %w", cal.Source, err))
+
+			// keep previous events if the calendar is temporarily unavailable
+			s.mu.RLock()
+			res = s.events[cal.Source]
+			s.mu.RUnlock()
+		}
+
+		events[cal.Source] = res
+	}
+
+	s.mu.Lock()
+	s.events = events
+	s.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// load reads and parses the calendar's events
+func (s *Scheduler) load(cal Calendar) ([]calendarEvent, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	if strings.HasPrefix(cal.Source, "http://") || strings.HasPrefix(cal.Source, "https://") {
+		b, err = request.NewHelper(s.log).GetBody(cal.Source)
+	} else {
+		b, err = os.ReadFile(cal.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := parseCalendar(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	var res []calendarEvent
+	for _, ev := range events {
+		if ev.Err != nil {
+			// log once instead of on every refresh
+			if key := cal.Source + "\x00" + ev.UID + "\x00" + ev.Summary; !s.unsupported[key] {
+				s.unsupported[key] = true
+				s.log.WARN.Printf("calendar %s: skipping event %q: %v", cal.Source, ev.Summary, ev.Err)
+			}
+			continue
+		}
+
+		action := Action{Loadpoint: cal.Loadpoint}
+		if err := parseAction(ev.Summary, &action); err != nil {
+			s.log.DEBUG.Printf("calendar %s: skipping event %q: %v", cal.Source, ev.Summary, err)
+			continue
+		}
+
+		res = append(res, calendarEvent{event: ev, Action: action})
+	}
+
+	return res, nil
+}
+
+// Due returns the actions triggered in (from, to] ordered by time
+func (s *Scheduler) Due(from, to time.Time) []Trigger {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []Trigger
+
+	for _, r := range s.config.Rules {
+		if !r.Active {
+			continue
+		}
+		for _, ts := range r.occurrences(from, to) {
+			res = append(res, Trigger{Time: ts, Action: r.Action})
+		}
+	}
+
+	for _, events := range s.events {
+		for _, ev := range events {
+			for _, ts := range ev.occurrences(from, to) {
+				res = append(res, Trigger{Time: ts, Action: ev.Action})
+			}
+		}
+	}
+
+	slices.SortStableFunc(res, func(a, b Trigger) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return res
+}
@@ -0,0 +1,193 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleValidate(t *testing.T) {
+	for _, r := range []Rule{
+		{Weekdays: []int{7}, Time: "22:00"},
+		{Weekdays: []int{1}, Time: "25:00"},
+		{Weekdays: []int{1}, Time: "22:00", Tz: "Foo/Bar"},
+		{Weekdays: []int{1}, Time: "22:00", Action: Action{Mode: "foo"}},
+	} {
+		assert.Error(t, r.Validate(), r)
+	}
+
+	assert.NoError(t, Rule{Weekdays: []int{1}, Time: "22:00", Tz: "Europe/Berlin", Action: Action{Mode: api.ModePV}}.Validate())
+}
+
+func TestSchedulerRules(t *testing.T) {
+	s := New(util.NewLogger("foo"))
+
+	// weekdays 22:00 -> pv, 06:00 -> minpv
+	require.NoError(t, s.SetConfig(Config{
+		Rules: []Rule{
+			{Weekdays: []int{1, 2, 3, 4, 5}, Time: "22:00", Tz: "UTC", Active: true, Action: Action{Mode: api.ModePV}},
+			{Weekdays: []int{1, 2, 3, 4, 5}, Time: "06:00", Tz: "UTC", Active: true, Action: Action{Mode: api.ModeMinPV}},
+			{Weekdays: []int{1, 2, 3, 4, 5}, Time: "12:00", Tz: "UTC", Active: false, Action: Action{Mode: api.ModeNow}},
+		},
+	}))
+
+	// Monday
+	monday := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	res := s.Due(monday.Add(21*time.Hour+59*time.Minute+30*time.Second), monday.Add(22*time.Hour))
+	require.Len(t, res, 1)
+	assert.Equal(t, api.ModePV, res[0].Mode)
+	assert.Equal(t, monday.Add(22*time.Hour), res[0].Time)
+
+	// trigger time is only due once
+	assert.Empty(t, s.Due(monday.Add(22*time.Hour), monday.Add(22*time.Hour+30*time.Second)))
+
+	// inactive rule
+	assert.Empty(t, s.Due(monday.Add(11*time.Hour), monday.Add(13*time.Hour)))
+
+	// whole week in order
+	res = s.Due(monday, monday.AddDate(0, 0, 7))
+	require.Len(t, res, 10)
+	assert.Equal(t, api.ModeMinPV, res[0].Mode)
+	assert.Equal(t, api.ModePV, res[len(res)-1].Mode)
+
+	// weekend
+	saturday := monday.AddDate(0, 0, 5)
+	assert.Empty(t, s.Due(saturday, saturday.AddDate(0, 0, 1)))
+}
+
+func TestSchedulerCalendar(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20250602T220000Z\r\n" +
+		"SUMMARY:Night mode=now limitsoc=80\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20250603T060000\r\n" +
+		"SUMMARY:loadpoint=2 maxcurrent=1\r\n" +
+		" 0 batterydischargecontrol=true\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:daily\r\n" +
+		"DTSTART:20250603T200000Z\r\n" +
+		"RRULE:FREQ=DAILY;COUNT=4\r\n" +
+		"EXDATE:20250604T200000Z\r\n" +
+		"SUMMARY:Daily mode=pv\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:daily\r\n" +
+		"RECURRENCE-ID:20250605T200000Z\r\n" +
+		"DTSTART:20250605T210000Z\r\n" +
+		"SUMMARY:Daily mode=minpv\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20250603T100000Z\r\n" +
+		"RRULE:FREQ=MONTHLY\r\n" +
+		"SUMMARY:Monthly mode=off\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20250604\r\n" +
+		"SUMMARY:Holiday\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	file := filepath.Join(t.TempDir(), "schedule.ics")
+	require.NoError(t, os.WriteFile(file, []byte(ics), 0o644))
+
+	s := New(util.NewLogger("foo"))
+	require.NoError(t, s.SetConfig(Config{
+		Calendars: []Calendar{{Source: file, Loadpoint: 1, Active: true}},
+	}))
+	require.NoError(t, s.Refresh())
+
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	res := s.Due(start, start.AddDate(0, 0, 7))
+	require.Len(t, res, 5)
+
+	assert.Equal(t, time.Date(2025, 6, 2, 22, 0, 0, 0, time.UTC), res[0].Time)
+	assert.Equal(t, 1, res[0].Loadpoint)
+	assert.Equal(t, api.ModeNow, res[0].Mode)
+	assert.Equal(t, 80, *res[0].LimitSoc)
+
+	assert.Equal(t, time.Date(2025, 6, 3, 4, 0, 0, 0, time.UTC), res[1].Time.UTC())
+	assert.Equal(t, 2, res[1].Loadpoint)
+	assert.Equal(t, 10.0, *res[1].MaxCurrent)
+	assert.True(t, *res[1].BatteryDischargeControl)
+
+	// recurring event without excluded and modified occurrence
+	assert.Equal(t, time.Date(2025, 6, 3, 20, 0, 0, 0, time.UTC), res[2].Time)
+	assert.Equal(t, api.ModePV, res[2].Mode)
+	assert.Equal(t, time.Date(2025, 6, 5, 21, 0, 0, 0, time.UTC), res[3].Time)
+	assert.Equal(t, api.ModeMinPV, res[3].Mode)
+	assert.Equal(t, time.Date(2025, 6, 6, 20, 0, 0, 0, time.UTC), res[4].Time)
+	assert.Equal(t, api.ModePV, res[4].Mode)
+
+	// unavailable calendar keeps previous events
+	require.NoError(t, os.Remove(file))
+	assert.Error(t, s.Refresh())
+	assert.Len(t, s.Due(start, start.AddDate(0, 0, 7)), 5)
+}
+
+func TestRecurrence(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Monday
+	start := time.Date(2025, 3, 3, 22, 0, 0, 0, loc)
+
+	for _, tc := range []struct {
+		rule     string
+		from, to time.Time
+		res      []time.Time
+	}{
+		{
+			// every other week on Monday and Friday
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			start.Add(-time.Hour), start.AddDate(0, 0, 15),
+			[]time.Time{start, start.AddDate(0, 0, 4), start.AddDate(0, 0, 14)},
+		},
+		{
+			// skipping ahead keeps local time across daylight saving change
+			"FREQ=DAILY",
+			start.AddDate(0, 0, 100), start.AddDate(0, 0, 101),
+			[]time.Time{start.AddDate(0, 0, 101)},
+		},
+		{
+			// weekdays only until Wednesday
+			"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20250312",
+			start, start.AddDate(0, 0, 14),
+			[]time.Time{start.AddDate(0, 0, 1), start.AddDate(0, 0, 2), start.AddDate(0, 0, 3), start.AddDate(0, 0, 4), start.AddDate(0, 0, 7), start.AddDate(0, 0, 8), start.AddDate(0, 0, 9)},
+		},
+		{
+			// counted from start
+			"FREQ=WEEKLY;COUNT=2",
+			start.AddDate(0, 0, 1), start.AddDate(0, 0, 30),
+			[]time.Time{start.AddDate(0, 0, 7)},
+		},
+	} {
+		t.Log(tc.rule)
+
+		r, err := parseRecurrence(tc.rule)
+		require.NoError(t, err)
+		assert.Equal(t, tc.res, r.occurrences(start, tc.from, tc.to))
+	}
+
+	for _, rule := range []string{"FREQ=MONTHLY", "FREQ=WEEKLY;BYDAY=1MO", "FREQ=DAILY;BYHOUR=8", "COUNT=2"} {
+		_, err := parseRecurrence(rule)
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestRuleLocalTimezone(t *testing.T) {
+	r := Rule{Weekdays: []int{1}, Time: "22:00", Active: true}
+	require.NoError(t, r.Validate())
+
+	monday := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, []time.Time{time.Date(2025, 6, 2, 22, 0, 0, 0, time.Local)}, r.occurrences(monday, monday.AddDate(0, 0, 1)))
+}
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// CurvePoint is a single sample of the charging curve of a session
+type CurvePoint struct {
+	ID             uint      `json:"-" csv:"-" gorm:"primarykey"`
+	SessionID      uint      `json:"sessionId" csv:"-" gorm:"index"`
+	Timestamp      time.Time `json:"timestamp"`
+	Power          float64   `json:"power" csv:"Power (W)" format:"int"`
+	Phases         int       `json:"phases" csv:"Phases"`
+	OfferedCurrent float64   `json:"offeredCurrent" csv:"Offered Current (A)"`
+	Soc            *float64  `json:"soc" csv:"SoC (%)"`                    // vehicle soc, reported or estimated
+	EstimatedSoc   *float64  `json:"estimatedSoc" csv:"Estimated SoC (%)"` // speed estimator soc if estimation is active
+}
+
+// TableName implements the gorm.Tabler interface
+func (CurvePoint) TableName() string {
+	return "session_curves"
+}
+
+// Curve is the charging curve of a session
+type Curve []CurvePoint
+
+var _ api.CsvWriter = (*Curve)(nil)
+
+// WriteCsv implements the api.CsvWriter interface
+func (t *Curve) WriteCsv(ctx context.Context, w io.Writer) error {
+	return writeCsv(ctx, w, "sessions.curve", *t)
+}
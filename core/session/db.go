@@ -1,6 +1,8 @@
 package session
 
 import (
+	"time"
+
 	"github.com/evcc-io/evcc/util"
 	"gorm.io/gorm"
 )
@@ -14,7 +16,7 @@ type DB struct {
 
 // NewStore creates a session store
 func NewStore(name string, db *gorm.DB) (*DB, error) {
-	err := db.AutoMigrate(new(Session))
+	err := db.AutoMigrate(new(Session), new(CurvePoint))
 
 	sessiondb := &DB{
 		log:  util.NewLogger("db"),
@@ -45,6 +47,20 @@ func (s *DB) Persist(session interface{}) {
 	}
 }
 
+// PersistCurvePoint adds a sample to the charging curve of a session
+func (s *DB) PersistCurvePoint(p CurvePoint) {
+	if err := s.db.Create(&p).Error; err != nil {
+		s.log.ERROR.Printf("persist curve: %v", err)
+	}
+}
+
+// Curve returns the charging curve of the session with given id
+func (s *DB) Curve(id uint) (Curve, error) {
+	var res Curve
+	tx := s.db.Where("session_id = ?", id).Order("timestamp").Find(&res)
+	return res, tx.Error
+}
+
 // Return sessions
 // TODO make this part of server/db
 func (s *DB) Sessions() (Sessions, error) {
@@ -53,6 +69,13 @@ func (s *DB) Sessions() (Sessions, error) {
 	return res, tx.Error
 }
 
+// VehicleSessions returns the sessions of the vehicle finished since the given time across all loadpoints
+func VehicleSessions(db *gorm.DB, vehicle string, since time.Time) (Sessions, error) {
+	var res Sessions
+	tx := db.Where("vehicle = ? AND finished >= ?", vehicle, since).Order("finished").Find(&res)
+	return res, tx.Error
+}
+
 func (s *DB) ClosePendingSessionsInHistory(chargeMeterTotal float64) error {
 	var res Sessions
 	if tx := s.db.Find(&res, map[string]interface{}{"finished": "0001-01-01 00:00:00+00:00", "Loadpoint": s.name}); tx.Error != nil {
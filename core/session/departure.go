@@ -0,0 +1,150 @@
+package session
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	DepartureLookback   = 8 * 7 * 24 * time.Hour // history considered for learning departures
+	DepartureConfidence = 0.5                    // min confidence of learned departures for creating plans
+
+	departureMinDuration = time.Hour        // min connection duration of a session to count as departure
+	departureWindow      = 30 * time.Minute // max deviation from the typical departure time
+	departureMinSamples  = 3                // min departures per weekday
+	departureRounding    = 15 * time.Minute // learned times are rounded down to this resolution
+)
+
+// Departure is the typical departure time of a vehicle on a weekday
+type Departure struct {
+	Weekday    int     `json:"weekday"`    // 0-6 (Sunday-Saturday)
+	Time       string  `json:"time"`       // HH:MM
+	Samples    int     `json:"samples"`    // number of departures close to the typical time
+	Confidence float64 `json:"confidence"` // share of observed weekdays with departure close to the typical time
+}
+
+// DepartureTimezone returns the timezone departures are learned and planned in
+func DepartureTimezone() (string, *time.Location) {
+	tz := util.Timezone()
+	if loc, err := time.LoadLocation(tz); err == nil {
+		return tz, loc
+	}
+	return time.Local.String(), time.Local
+}
+
+// Departures learns the typical departure time per weekday from the sessions' finish times
+// within the lookback period. Only the first departure of each day is considered.
+func (t Sessions) Departures(now time.Time, loc *time.Location) []Departure {
+	since := now.Add(-DepartureLookback)
+
+	first := now
+	days := make(map[time.Time]time.Time) // first departure per day
+
+	for _, s := range t {
+		if s.Finished.IsZero() || s.Finished.Before(since) || s.Finished.After(now) || s.Finished.Sub(s.Created) < departureMinDuration {
+			continue
+		}
+
+		ts := s.Finished.In(loc)
+		if ts.Before(first) {
+			first = ts
+		}
+
+		day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, loc)
+		if prev, ok := days[day]; !ok || ts.Before(prev) {
+			days[day] = ts
+		}
+	}
+
+	// time of day of departures by weekday
+	var byWeekday [7][]time.Duration
+	for day, ts := range days {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], ts.Sub(day))
+	}
+
+	var res []Departure
+
+	for wd, tods := range byWeekday {
+		if len(tods) < departureMinSamples {
+			continue
+		}
+
+		slices.Sort(tods)
+		median := tods[len(tods)/2]
+
+		var samples int
+		for _, tod := range tods {
+			if (tod - median).Abs() <= departureWindow {
+				samples++
+			}
+		}
+
+		if samples < departureMinSamples {
+			continue
+		}
+
+		tod := median.Truncate(departureRounding)
+
+		res = append(res, Departure{
+			Weekday:    wd,
+			Time:       fmt.Sprintf("%02d:%02d", int(tod.Hours()), int(tod.Minutes())%60),
+			Samples:    samples,
+			Confidence: min(1, float64(samples)/float64(weekdays(first, now, time.Weekday(wd)))),
+		})
+	}
+
+	return res
+}
+
+// weekdays returns the number of given weekdays between from and to
+func weekdays(from, to time.Time, wd time.Weekday) int {
+	res := int(to.Sub(from).Hours()/24) / 7
+
+	// remaining days
+	for d := from.AddDate(0, 0, res*7); !d.After(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == wd {
+			res++
+		}
+	}
+
+	return max(1, res)
+}
+
+// RepeatingPlans creates repeating plans for the departures with at least the given confidence.
+// Weekdays with identical departure times are combined into a single plan.
+func RepeatingPlans(departures []Departure, confidence float64, soc int, tz string) []api.RepeatingPlanStruct {
+	var res []api.RepeatingPlanStruct
+
+	for _, d := range departures {
+		if d.Confidence < confidence {
+			continue
+		}
+
+		idx := slices.IndexFunc(res, func(p api.RepeatingPlanStruct) bool {
+			return p.Time == d.Time
+		})
+
+		if idx < 0 {
+			res = append(res, api.RepeatingPlanStruct{
+				Time:   d.Time,
+				Tz:     tz,
+				Soc:    soc,
+				Active: true,
+			})
+			idx = len(res) - 1
+		}
+
+		res[idx].Weekdays = append(res[idx].Weekdays, d.Weekday)
+	}
+
+	slices.SortFunc(res, func(a, b api.RepeatingPlanStruct) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+
+	return res
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepartures(t *testing.T) {
+	// Monday
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 28)
+
+	var sessions Sessions
+	add := func(day int, tod time.Duration, connected time.Duration) {
+		finished := start.AddDate(0, 0, day).Add(tod)
+		sessions = append(sessions, Session{Created: finished.Add(-connected), Finished: finished})
+	}
+
+	for week := range 4 {
+		// weekdays around 07:40
+		for day := range 5 {
+			add(7*week+day, 7*time.Hour+time.Duration(35+week*3)*time.Minute, 10*time.Hour)
+
+			// short daytime session
+			add(7*week+day, 18*time.Hour, 30*time.Minute)
+		}
+
+		// irregular saturdays
+		add(7*week+5, time.Duration(8+3*week)*time.Hour, 10*time.Hour)
+	}
+
+	// monday departures missing in second week
+	sessions = append(sessions[:11], sessions[13:]...)
+
+	res := sessions.Departures(now, time.UTC)
+	require.Len(t, res, 5)
+
+	assert.Equal(t, Departure{Weekday: 1, Time: "07:30", Samples: 3, Confidence: 0.75}, res[0])
+	for _, d := range res[1:] {
+		assert.Equal(t, "07:30", d.Time)
+		assert.Equal(t, 4, d.Samples)
+		assert.Equal(t, 1.0, d.Confidence)
+	}
+
+	plans := RepeatingPlans(res, 0.8, 80, "UTC")
+	assert.Equal(t, []api.RepeatingPlanStruct{
+		{Weekdays: []int{2, 3, 4, 5}, Time: "07:30", Tz: "UTC", Soc: 80, Active: true},
+	}, plans)
+
+	assert.Len(t, RepeatingPlans(res, 0.5, 80, "UTC")[0].Weekdays, 5)
+}
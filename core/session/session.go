@@ -42,21 +42,23 @@ type Sessions []Session
 
 var _ api.CsvWriter = (*Sessions)(nil)
 
-func (t *Sessions) writeHeader(ctx context.Context, ww *csv.Writer) error {
+// writeHeader writes the csv header for the fields of given struct. Captions are
+// localized using the prefix, falling back to the csv tag or field name.
+func writeHeader(ctx context.Context, ww *csv.Writer, prefix string, v any) error {
 	localizer := locale.Localizer
 	if val := ctx.Value(locale.Locale).(string); val != "" {
 		localizer = i18n.NewLocalizer(locale.Bundle, val, locale.Language)
 	}
 
 	var row []string
-	for _, f := range structs.Fields(Session{}) {
+	for _, f := range structs.Fields(v) {
 		csv := f.Tag("csv")
 		if csv == "-" {
 			continue
 		}
 
 		caption, err := localizer.Localize(&locale.Config{
-			MessageID: "sessions.csv." + strings.ToLower(f.Name()),
+			MessageID: prefix + ".csv." + strings.ToLower(f.Name()),
 		})
 		if err != nil {
 			if csv != "" {
@@ -92,7 +94,7 @@ func formatValue(mp *message.Printer, value any, digits int) string {
 	}
 }
 
-func (t *Sessions) writeRow(ww *csv.Writer, mp *message.Printer, r Session) error {
+func writeRow(ww *csv.Writer, mp *message.Printer, r any) error {
 	var row []string
 	for _, f := range structs.Fields(r) {
 		if f.Tag("csv") == "-" {
@@ -112,8 +114,8 @@ func (t *Sessions) writeRow(ww *csv.Writer, mp *message.Printer, r Session) erro
 	return ww.Write(row)
 }
 
-// WriteCsv implements the api.CsvWriter interface
-func (t *Sessions) WriteCsv(ctx context.Context, w io.Writer) error {
+// writeCsv writes the rows as localized csv
+func writeCsv[T any](ctx context.Context, w io.Writer, prefix string, rows []T) error {
 	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
 		return err
 	}
@@ -136,13 +138,14 @@ func (t *Sessions) WriteCsv(ctx context.Context, w io.Writer) error {
 		ww.Comma = ';'
 	}
 
-	if err := t.writeHeader(ctx, ww); err != nil {
+	var zero T
+	if err := writeHeader(ctx, ww, prefix, zero); err != nil {
 		return err
 	}
 
 	mp := message.NewPrinter(tag)
-	for _, r := range *t {
-		if err := t.writeRow(ww, mp, r); err != nil {
+	for _, r := range rows {
+		if err := writeRow(ww, mp, r); err != nil {
 			return err
 		}
 	}
@@ -151,3 +154,8 @@ func (t *Sessions) WriteCsv(ctx context.Context, w io.Writer) error {
 
 	return ww.Error()
 }
+
+// WriteCsv implements the api.CsvWriter interface
+func (t *Sessions) WriteCsv(ctx context.Context, w io.Writer) error {
+	return writeCsv(ctx, w, "sessions", *t)
+}
@@ -3,8 +3,10 @@ package core
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -16,14 +18,18 @@ import (
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core/circuit"
 	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/forecast"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/peak"
 	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/prioritizer"
+	"github.com/evcc-io/evcc/core/schedule"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/plugin"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/db/settings"
@@ -57,6 +63,13 @@ type measurement struct {
 	Capacity      *float64  `json:"capacity,omitempty"`
 	Soc           *float64  `json:"soc,omitempty"`
 	Controllable  *bool     `json:"controllable,omitempty"`
+
+	// battery only
+	Mode           string   `json:"mode,omitempty"`
+	PrioritySoc    *float64 `json:"prioritySoc,omitempty"`
+	BufferSoc      *float64 `json:"bufferSoc,omitempty"`
+	BufferStartSoc *float64 `json:"bufferStartSoc,omitempty"`
+	GridCharge     *bool    `json:"gridCharge,omitempty"`
 }
 
 var _ site.API = (*Site)(nil)
@@ -65,11 +78,13 @@ var _ site.API = (*Site)(nil)
 type Site struct {
 	uiChan       chan<- util.Param // client push messages
 	lpUpdateChan chan *Loadpoint
+	pushChan     chan<- push.Event
 
 	*Health
 
 	sync.RWMutex
-	log *util.Logger
+	log   *util.Logger
+	clock clock.Clock
 
 	// configuration
 	Title         string       `mapstructure:"title"`         // UI title
@@ -77,8 +92,16 @@ type Site struct {
 	ResidualPower float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
 	Meters        MetersConfig `mapstructure:"meters"`        // Meter references
 
+	MaxGridExport    *float64       `mapstructure:"maxGridExport"`    // Grid export limit (W)
+	GetMaxGridExport *plugin.Config `mapstructure:"getMaxGridExport"` // Dynamic grid export limit (W)
+
+	Batteries        map[string]BatteryConfig `mapstructure:"batteries"`        // Per-battery settings by meter name
+	BatteryOptimizer BatteryOptimizerConfig   `mapstructure:"batteryOptimizer"` // Price- and forecast-driven battery control
+	PeakShaving      PeakShavingConfig        `mapstructure:"peakShaving"`      // Grid import peak limitation
+
 	// meters
 	circuit       api.Circuit                // Circuit
+	overloads     map[api.Circuit]int        // notified circuit overloads
 	gridMeter     api.Meter                  // Grid usage meter
 	pvMeters      []config.Device[api.Meter] // PV generation meters
 	batteryMeters []config.Device[api.Meter] // Battery charging meters
@@ -91,21 +114,55 @@ type Site struct {
 	bufferStartSoc          float64  // start charging on battery above this Soc
 	batteryDischargeControl bool     // prevent battery discharge for fast and planned charging
 	batteryGridChargeLimit  *float64 // grid charging limit
-
-	loadpoints  []*Loadpoint             // Loadpoints
-	tariffs     *tariff.Tariffs          // Tariffs
-	coordinator *coordinator.Coordinator // Vehicles
-	prioritizer *prioritizer.Prioritizer // Power budgets
-	stats       *Stats                   // Stats
-	fcstEnergy  *meterEnergy
-	pvEnergy    map[string]*meterEnergy
+	batteryGridChargePower  float64  // grid charging power, max power if zero
+
+	// grid export limit
+	getMaxGridExport     func() (float64, error) // dynamic grid export limit
+	gridExportExcess     float64                 // export above the limit
+	exportAtLimit        time.Time               // export at the limit since
+	exportProbing        bool                    // surplus offered for revealing curtailed pv
+	exportProbePause     time.Time               // no probing until
+	pvLimited            bool                    // pv curtailed to enforce the export limit
+	pvLimit              float64                 // pv power limit
+	pvUnlimitedPower     float64                 // pv power before curtailment
+	curtailedEnergy      *meterEnergy            // estimated curtailed pv energy
+	curtailedPersisted   float64                 // curtailed pv energy last persisted
+	curtailedPersistedAt time.Time               // curtailed pv energy last persisted at
+
+	// grid import peak shaving
+	peakTracker      *peak.Tracker // quarter-hour grid import tracking
+	peakStatus       peak.Status   // published peak shaving state
+	peakShaving      bool          // import above the peak limit
+	peakCircuitPower float64       // root circuit max power before peak shaving
+	peakCircuitLimit float64       // root circuit max power applied by peak shaving, zero if not limited
+
+	batteryPlanner    *planner.BatteryPlanner // battery optimizer
+	batteryPlan       planner.BatteryPlan     // planned battery modes
+	batteryPlanFailed bool                    // battery planning failed in the previous cycle
+
+	loadpoints   []*Loadpoint             // Loadpoints
+	tariffs      *tariff.Tariffs          // Tariffs
+	coordinator  *coordinator.Coordinator // Vehicles
+	prioritizer  *prioritizer.Prioritizer // Power budgets
+	stats        *Stats                   // Stats
+	fcstEnergy   *meterEnergy
+	pvEnergy     map[string]*meterEnergy
+	homeForecast *forecast.Home // learned household consumption
+	scheduler    *schedule.Scheduler
+	scheduled    time.Time // last schedule evaluation
+
+	scheduleRefresh chan struct{} // request calendar reload
+
+	departuresUpdated time.Time // last update of plans from learned departures
 
 	// cached state
 	gridPower                float64         // Grid power
 	pvPower                  float64         // PV power
+	pvPowers                 []float64       // Individual PV meter power
 	excessDCPower            float64         // PV excess DC charge power (hybrid only)
 	auxPower                 float64         // Aux power
 	batteryPower             float64         // Battery power (charge negative, discharge positive)
+	batteries                []batteryState  // Individual battery state
 	batterySoc               float64         // Battery soc
 	batteryCapacity          float64         // Battery capacity
 	batteryMode              api.BatteryMode // Battery mode (runtime only, not persisted)
@@ -122,6 +179,30 @@ type MetersConfig struct {
 	AuxMetersRef     []string `mapstructure:"aux"`     // Auxiliary meters
 }
 
+// BatteryConfig contains a battery's own settings. Unset values default to the site's battery settings.
+type BatteryConfig struct {
+	PrioritySoc    *float64 `mapstructure:"prioritySoc"`    // prefer battery up to this Soc
+	BufferSoc      *float64 `mapstructure:"bufferSoc"`      // continue charging on battery above this Soc
+	BufferStartSoc *float64 `mapstructure:"bufferStartSoc"` // start charging on battery above this Soc
+	GridCharge     *bool    `mapstructure:"gridCharge"`     // allow grid charging, default true
+	Control        *bool    `mapstructure:"control"`        // allow battery mode control, default true
+}
+
+// BatteryOptimizerConfig contains the battery optimizer configuration
+type BatteryOptimizerConfig struct {
+	Enabled           bool    `mapstructure:"enabled"`           // Plan battery modes from tariffs and forecasts
+	Efficiency        float64 `mapstructure:"efficiency"`        // Round-trip efficiency (0..1)
+	MaxChargePower    float64 `mapstructure:"maxChargePower"`    // Max battery charge power (W)
+	MaxDischargePower float64 `mapstructure:"maxDischargePower"` // Max battery discharge power (W)
+}
+
+// PeakShavingConfig contains the grid import peak shaving configuration
+type PeakShavingConfig struct {
+	Enabled   bool    `mapstructure:"enabled"`   // Limit the quarter-hour average grid import
+	Target    float64 `mapstructure:"target"`    // Max quarter-hour average import (W), learned from the month's peak if zero
+	MinTarget float64 `mapstructure:"minTarget"` // Min learned target (W)
+}
+
 // NewSiteFromConfig creates a new site
 func NewSiteFromConfig(other map[string]interface{}) (*Site, error) {
 	site := NewSite()
@@ -160,10 +241,22 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 
 	tariff := site.GetTariff(api.TariffUsagePlanner)
 
+	// plan all loadpoints jointly, including expected solar surplus
+	sp := &sitePlanner{
+		site:    site,
+		planner: planner.NewSitePlanner(log, tariff, site.GetTariff(api.TariffUsageSolar), planner.WithBaseload(site.homeForecast)),
+	}
+
+	// plan home battery modes
+	if site.BatteryOptimizer.Enabled {
+		site.batteryPlanner = planner.NewBatteryPlanner(log, tariff, site.GetTariff(api.TariffUsageFeedIn), site.GetTariff(api.TariffUsageSolar), site.homeForecast)
+	}
+
 	// give loadpoints access to vehicles and database
 	for _, lp := range loadpoints {
 		lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
 		lp.planner = planner.New(lp.log, tariff)
+		lp.sitePlanner = sp
 
 		if db.Instance != nil {
 			var err error
@@ -233,6 +326,35 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 		site.auxMeters = append(site.auxMeters, dev)
 	}
 
+	// dynamic grid export limit
+	var err error
+	if site.getMaxGridExport, err = site.GetMaxGridExport.FloatGetter(util.WithLogger(context.TODO(), site.log)); err != nil {
+		return fmt.Errorf("max grid export: %w", err)
+	}
+
+	// peak shaving
+	if site.PeakShaving.Enabled {
+		if site.gridMeter == nil {
+			return errors.New("peak shaving requires grid meter")
+		}
+
+		site.peakTracker = peak.New()
+
+		if site.circuit == nil {
+			site.log.WARN.Println("peak shaving: no root circuit, loadpoints are not limited")
+		}
+
+		// restore root circuit limit on shutdown
+		shutdown.Register(site.restoreCircuitPower)
+	}
+
+	// remove pv curtailment on shutdown
+	shutdown.Register(func() {
+		if site.pvLimited {
+			site.removePvLimit()
+		}
+	})
+
 	// revert battery mode on shutdown
 	shutdown.Register(func() {
 		if mode := site.GetBatteryMode(); batteryModeModified(mode) {
@@ -248,10 +370,18 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 // NewSite creates a Site with sane defaults
 func NewSite() *Site {
 	site := &Site{
-		log:        util.NewLogger("site"),
-		Voltage:    230, // V
-		pvEnergy:   make(map[string]*meterEnergy),
-		fcstEnergy: &meterEnergy{clock: clock.New()},
+		log:             util.NewLogger("site"),
+		clock:           clock.New(),
+		Voltage:         230, // V
+		pvEnergy:        make(map[string]*meterEnergy),
+		fcstEnergy:      &meterEnergy{clock: clock.New()},
+		curtailedEnergy: &meterEnergy{clock: clock.New()},
+		homeForecast:    forecast.NewHome(),
+		scheduler:       schedule.New(util.NewLogger("schedule")),
+		scheduleRefresh: make(chan struct{}, 1),
+		PeakShaving: PeakShavingConfig{
+			MinTarget: 2500, // W, minimum billed peak of Belgian capacity tariffs
+		},
 	}
 
 	return site
@@ -316,6 +446,33 @@ func (site *Site) restoreSettings() error {
 	if v, err := settings.Float(keys.BatteryGridChargeLimit); err == nil {
 		site.SetBatteryGridChargeLimit(&v)
 	}
+	if v, err := settings.Float(keys.BatteryGridChargePower); err == nil {
+		if err := site.SetBatteryGridChargePower(v); err != nil {
+			return err
+		}
+	}
+
+	var monthlyPeak peak.Peak
+	if err := settings.Json(keys.PeakShaving, &monthlyPeak); err == nil && site.peakTracker != nil {
+		site.peakTracker.Restore(monthlyPeak)
+	}
+
+	if v, err := settings.Float(keys.PvCurtailedEnergy); err == nil {
+		site.curtailedEnergy.Accumulated = v
+		site.curtailedPersisted = v
+	}
+
+	var profile forecast.Profile
+	if err := settings.Json(keys.HomeProfile, &profile); err == nil {
+		site.homeForecast.Restore(profile)
+	}
+
+	var schedules schedule.Config
+	if err := settings.Json(keys.Schedules, &schedules); err == nil {
+		if err := site.scheduler.SetConfig(schedules); err != nil {
+			site.log.ERROR.Println("schedules:", err)
+		}
+	}
 
 	// restore accumulated energy
 	pvEnergy := make(map[string]meterEnergy)
@@ -559,6 +716,9 @@ func (site *Site) updatePvMeters() {
 	site.pvPower = lo.SumBy(mm, func(m measurement) float64 {
 		return max(0, m.Power)
 	})
+	site.pvPowers = lo.Map(mm, func(m measurement, _ int) float64 {
+		return m.Power
+	})
 	site.excessDCPower = lo.SumBy(mm, func(m measurement) float64 {
 		return math.Abs(m.ExcessDCPower)
 	})
@@ -611,6 +771,8 @@ func (site *Site) updateBatteryMeters() {
 	}
 
 	mm := site.collectMeters("battery", site.batteryMeters)
+	batteries := make([]batteryState, len(site.batteryMeters))
+	batMode := site.GetBatteryMode()
 
 	for i, dev := range site.batteryMeters {
 		meter := dev.Instance()
@@ -635,30 +797,32 @@ func (site *Site) updateBatteryMeters() {
 			}
 		}
 
+		cc, _ := site.batteryConfig(dev)
 		_, controllable := meter.(api.BatteryController)
+		controllable = controllable && cc.controlled()
 
 		mm[i].Soc = lo.ToPtr(batSoc)
 		mm[i].Capacity = lo.ToPtr(capacity)
 		mm[i].Controllable = lo.ToPtr(controllable)
-	}
+		mm[i].PrioritySoc = cc.PrioritySoc
+		mm[i].BufferSoc = cc.BufferSoc
+		mm[i].BufferStartSoc = cc.BufferStartSoc
+		mm[i].GridCharge = cc.GridCharge
 
-	batterySocAcc := lo.SumBy(mm, func(m measurement) float64 {
-		// weigh soc by capacity
-		if *m.Capacity > 0 {
-			return *m.Soc * *m.Capacity
+		if mode := cc.mode(batMode); controllable && mode != api.BatteryUnknown {
+			mm[i].Mode = mode.String()
 		}
-		return *m.Soc
-	})
-	totalCapacity := lo.SumBy(mm, func(m measurement) float64 {
-		return *m.Capacity
-	})
 
-	// convert weighed socs to total soc
-	if totalCapacity == 0 {
-		totalCapacity = float64(len(site.batteryMeters))
+		batteries[i] = batteryState{
+			soc:      batSoc,
+			capacity: capacity,
+			power:    mm[i].Power,
+			config:   cc,
+		}
 	}
-	site.batterySoc = batterySocAcc / totalCapacity
-	site.batteryCapacity = totalCapacity
+
+	site.batteries = batteries
+	site.batterySoc, site.batteryCapacity = aggregateBatteries(batteries)
 
 	site.batteryPower = lo.SumBy(mm, func(m measurement) float64 {
 		return m.Power
@@ -789,9 +953,12 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 		site.publish(keys.Grid, measurement{Power: site.gridPower})
 	}
 
+	// batteries sharing the same settings
+	groups := site.batteryGroups()
+
 	// ensure safe default for residual power
 	residualPower := site.GetResidualPower()
-	if len(site.batteryMeters) > 0 && site.batterySoc < site.prioritySoc && residualPower <= 0 {
+	if slices.ContainsFunc(groups, func(g batteryGroup) bool { return g.soc < g.prioritySoc }) && residualPower <= 0 {
 		residualPower = 100 // Wsite.publish(keys.PvPower,
 	}
 
@@ -812,23 +979,23 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 	// handed to loadpoint
 	var batteryBuffered, batteryStart bool
 
-	if len(site.batteryMeters) > 0 {
-		site.RLock()
-		defer site.RUnlock()
-
+	for _, g := range groups {
 		// if battery is charging below prioritySoc give it priority
-		if site.batterySoc < site.prioritySoc && batteryPower < 0 {
-			site.log.DEBUG.Printf("battery has priority at soc %.0f%% (< %.0f%%)", site.batterySoc, site.prioritySoc)
-			batteryPower = 0
+		if g.soc < g.prioritySoc && g.power < 0 {
+			site.log.DEBUG.Printf("battery has priority at soc %.0f%% (< %.0f%%)", g.soc, g.prioritySoc)
+			batteryPower -= g.power
 			excessDCPower = 0
 		} else {
 			// if battery is above bufferSoc allow using it for charging
-			batteryBuffered = site.bufferSoc > 0 && site.batterySoc > site.bufferSoc
-			batteryStart = site.bufferStartSoc > 0 && site.batterySoc > site.bufferStartSoc
+			batteryBuffered = batteryBuffered || g.bufferSoc > 0 && g.soc > g.bufferSoc
+			batteryStart = batteryStart || g.bufferStartSoc > 0 && g.soc > g.bufferStartSoc
 		}
 	}
 
-	sitePower := site.gridPower + batteryPower + excessDCPower + residualPower - site.auxPower - flexiblePower
+	// surplus hidden by the grid export limit
+	exportSurplus := site.updateGridExport()
+
+	sitePower := site.gridPower + batteryPower + excessDCPower + residualPower - site.auxPower - flexiblePower - exportSurplus
 
 	// handle priority
 	var flexStr string
@@ -836,7 +1003,12 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 		flexStr = fmt.Sprintf(" (including %.0fW prioritized power)", flexiblePower)
 	}
 
-	site.log.DEBUG.Printf("site power: %.0fW"+flexStr, sitePower)
+	var exportStr string
+	if exportSurplus > 0 {
+		exportStr = fmt.Sprintf(" (including %.0fW surplus above export limit)", exportSurplus)
+	}
+
+	site.log.DEBUG.Printf("site power: %.0fW"+flexStr+exportStr, sitePower)
 
 	return sitePower, batteryBuffered, batteryStart, nil
 }
@@ -881,6 +1053,9 @@ func (site *Site) update(lp updater) {
 		site.log.WARN.Println("feed-in:", err)
 	}
 
+	// apply scheduled settings before loadpoints act on them
+	site.applySchedules()
+
 	// update loadpoints
 	totalChargePower := site.updateLoadpoints(consumption)
 
@@ -890,6 +1065,7 @@ func (site *Site) update(lp updater) {
 			site.log.ERROR.Println(err)
 		}
 
+		site.protectCircuits()
 		site.publishCircuits()
 	}
 
@@ -912,6 +1088,8 @@ func (site *Site) update(lp updater) {
 		site.log.WARN.Println("planner:", msg)
 	}
 
+	site.updateBatteryPlan()
+
 	batteryGridChargeActive := site.batteryGridChargeActive(rate)
 	site.publish(keys.BatteryGridChargeActive, batteryGridChargeActive)
 	site.updateBatteryMode(batteryGridChargeActive, rate)
@@ -922,12 +1100,26 @@ func (site *Site) update(lp updater) {
 		homePower = max(homePower, 0)
 		site.publish(keys.HomePower, homePower)
 
+		site.updateBatteryPower(homePower, rate)
+		site.updatePeakShaving(totalChargePower)
+
+		if site.homeForecast != nil && site.homeForecast.Update(homePower) {
+			if err := settings.SetJson(keys.HomeProfile, site.homeForecast.Profile()); err != nil {
+				site.log.ERROR.Println("home profile:", err)
+			}
+		}
+
 		// add battery charging power to homePower to ignore all consumption which does not occur on loadpoints
 		// fix for: https://github.com/evcc-io/evcc/issues/11032
 		nonChargePower := homePower + max(0, -site.batteryPower)
 		greenShareHome := site.greenShare(0, homePower)
 		greenShareLoadpoints := site.greenShare(nonChargePower, nonChargePower+totalChargePower)
 
+		// share the circuits' capacity according to the loadpoints' current demand
+		if site.circuit != nil {
+			site.circuit.Distribute(site.loadpointsAsCircuitDevices())
+		}
+
 		// TODO
 		lp.Update(
 			sitePower, max(0, site.batteryPower), consumption, feedin, batteryBuffered, batteryStart,
@@ -945,6 +1137,7 @@ func (site *Site) update(lp updater) {
 		site.log.ERROR.Println(err)
 	}
 
+	site.updateDepartures()
 	site.stats.Update(site)
 }
 
@@ -967,6 +1160,7 @@ func (site *Site) prepare() {
 	site.publish(keys.BufferStartSoc, site.bufferStartSoc)
 	site.publish(keys.BatteryMode, site.batteryMode)
 	site.publish(keys.BatteryDischargeControl, site.batteryDischargeControl)
+	site.publish(keys.BatteryGridChargePower, site.batteryGridChargePower)
 	site.publish(keys.ResidualPower, site.GetResidualPower())
 	site.publish(keys.SmartCostAvailable, site.isDynamicTariff(api.TariffUsagePlanner))
 	site.publish(keys.SmartFeedInPriorityAvailable, site.isDynamicTariff(api.TariffUsageFeedIn))
@@ -1002,6 +1196,7 @@ func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event)
 	}()
 
 	site.lpUpdateChan = make(chan *Loadpoint, 1) // 1 capacity to avoid deadlock
+	site.pushChan = pushChan
 
 	site.prepare()
 
@@ -1050,6 +1245,9 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 		go site.loopLoadpoints(loadpointChan)
 	}
 
+	pollChan := site.pollCircuits(stopC)
+	go site.refreshSchedules(stopC)
+
 	site.update(<-loadpointChan) // start immediately
 
 	for tick := time.Tick(interval); ; {
@@ -1058,6 +1256,8 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 			site.update(<-loadpointChan)
 		case lp := <-site.lpUpdateChan:
 			site.update(lp)
+		case c := <-pollChan:
+			site.pollCircuit(c)
 		case <-stopC:
 			return
 		}
@@ -3,6 +3,10 @@ package site
 import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/peak"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/schedule"
+	"github.com/evcc-io/evcc/core/session"
 )
 
 // API is the external site API
@@ -46,6 +50,10 @@ type API interface {
 	GetBatteryGridChargeLimit() *float64
 	// SetBatteryGridChargeLimit sets the grid charge limit
 	SetBatteryGridChargeLimit(limit *float64)
+	// GetBatteryGridChargePower gets the grid charge power
+	GetBatteryGridChargePower() float64
+	// SetBatteryGridChargePower sets the grid charge power, zero for max power
+	SetBatteryGridChargePower(float64) error
 
 	//
 	// power and energy
@@ -60,6 +68,8 @@ type API interface {
 
 	// GetTariff returns the respective tariff
 	GetTariff(api.TariffUsage) api.Tariff
+	// GetHomeForecast returns the expected household consumption
+	GetHomeForecast() (api.Rates, error)
 
 	//
 	// battery control
@@ -68,6 +78,12 @@ type API interface {
 	GetBatteryDischargeControl() bool
 	SetBatteryDischargeControl(bool) error
 
+	// GetBatteryPlan returns the battery optimizer's planned battery modes
+	GetBatteryPlan() (planner.BatteryPlan, error)
+
+	// GetPeakShaving returns the grid import peak shaving state
+	GetPeakShaving() peak.Status
+
 	//
 	// battery control external
 	//
@@ -76,4 +92,22 @@ type API interface {
 	GetBatteryModeExternal() api.BatteryMode
 	// SetBatteryModeExternal sets the external battery mode
 	SetBatteryModeExternal(api.BatteryMode)
+
+	//
+	// departures
+	//
+
+	// GetDepartures returns the vehicle's departure times learned from its charging sessions
+	GetDepartures(vehicle string) ([]session.Departure, error)
+	// ApplyDepartures creates repeating plans for the vehicle's learned departures
+	ApplyDepartures(vehicle string) error
+
+	//
+	// schedules
+	//
+
+	// GetSchedules returns the scheduler configuration
+	GetSchedules() schedule.Config
+	// SetSchedules sets the scheduler configuration
+	SetSchedules(schedule.Config) error
 }
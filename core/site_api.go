@@ -289,6 +289,14 @@ func (site *Site) GetTariff(tariff api.TariffUsage) api.Tariff {
 	return site.tariffs.Get(tariff)
 }
 
+// GetHomeForecast returns the expected household consumption in W
+func (site *Site) GetHomeForecast() (api.Rates, error) {
+	if site.homeForecast == nil {
+		return nil, errors.New("no home forecast")
+	}
+	return site.homeForecast.Rates()
+}
+
 // GetBatteryDischargeControl returns the battery control mode (no discharge only)
 func (site *Site) GetBatteryDischargeControl() bool {
 	site.RLock()
@@ -337,6 +345,33 @@ func (site *Site) SetBatteryGridChargeLimit(val *float64) {
 	}
 }
 
+// GetBatteryGridChargePower returns the grid charging power
+func (site *Site) GetBatteryGridChargePower() float64 {
+	site.RLock()
+	defer site.RUnlock()
+	return site.batteryGridChargePower
+}
+
+// SetBatteryGridChargePower sets the grid charging power. Zero charges at max power.
+func (site *Site) SetBatteryGridChargePower(val float64) error {
+	site.log.DEBUG.Println("set grid charge power:", val)
+
+	if val < 0 {
+		return errors.New("invalid grid charge power")
+	}
+
+	site.Lock()
+	defer site.Unlock()
+
+	if site.batteryGridChargePower != val {
+		site.batteryGridChargePower = val
+		settings.SetFloat(keys.BatteryGridChargePower, val)
+		site.publish(keys.BatteryGridChargePower, val)
+	}
+
+	return nil
+}
+
 // GetBatteryMode returns the battery mode
 func (site *Site) GetBatteryMode() api.BatteryMode {
 	site.RLock()
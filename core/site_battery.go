@@ -2,11 +2,15 @@ package core
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/samber/lo"
 )
 
 func batteryModeModified(mode api.BatteryMode) bool {
@@ -17,6 +21,125 @@ func (site *Site) batteryConfigured() bool {
 	return len(site.batteryMeters) > 0
 }
 
+// batteryState is the state of an individual battery
+type batteryState struct {
+	soc, capacity, power float64
+	config               BatteryConfig
+}
+
+// batteryGroup is a set of batteries sharing the same soc settings
+type batteryGroup struct {
+	soc, power                             float64
+	prioritySoc, bufferSoc, bufferStartSoc float64
+}
+
+// controlled returns true if the battery mode may be controlled
+func (cc BatteryConfig) controlled() bool {
+	return cc.Control == nil || *cc.Control
+}
+
+// ownSoc returns true if the battery has its own soc settings
+func (cc BatteryConfig) ownSoc() bool {
+	return cc.PrioritySoc != nil || cc.BufferSoc != nil || cc.BufferStartSoc != nil
+}
+
+// mode returns the battery mode to apply to the battery or unknown if the battery is not controlled.
+// Batteries not allowed to charge from grid are held instead of discharging into the charging batteries.
+func (cc BatteryConfig) mode(mode api.BatteryMode) api.BatteryMode {
+	switch {
+	case !cc.controlled():
+		return api.BatteryUnknown
+	case mode == api.BatteryCharge && cc.GridCharge != nil && !*cc.GridCharge:
+		return api.BatteryHold
+	default:
+		return mode
+	}
+}
+
+// batteryConfig returns the battery's own settings
+func (site *Site) batteryConfig(dev config.Device[api.Meter]) (BatteryConfig, bool) {
+	if len(site.Batteries) == 0 {
+		return BatteryConfig{}, false
+	}
+
+	// meter names may have been lowercased by the config parser
+	name := dev.Config().Name
+	for k, cc := range site.Batteries {
+		if strings.EqualFold(k, name) {
+			return cc, true
+		}
+	}
+
+	return BatteryConfig{}, false
+}
+
+// aggregateBatteries returns the batteries' soc weighed by capacity and their total capacity.
+// If any battery's capacity is unknown, the average soc is returned.
+func aggregateBatteries(batteries []batteryState) (float64, float64) {
+	if len(batteries) == 0 {
+		return 0, 0
+	}
+
+	capacity := lo.SumBy(batteries, func(b batteryState) float64 {
+		return b.capacity
+	})
+
+	if capacity <= 0 || lo.SomeBy(batteries, func(b batteryState) bool { return b.capacity <= 0 }) {
+		return lo.MeanBy(batteries, func(b batteryState) float64 {
+			return b.soc
+		}), capacity
+	}
+
+	soc := lo.SumBy(batteries, func(b batteryState) float64 {
+		return b.soc * b.capacity
+	}) / capacity
+
+	return soc, capacity
+}
+
+// batteryGroups returns the batteries with own soc settings individually and all other batteries
+// aggregated using the site's soc settings
+func (site *Site) batteryGroups() []batteryGroup {
+	site.RLock()
+	defer site.RUnlock()
+
+	var (
+		res    []batteryGroup
+		shared []batteryState
+	)
+
+	for _, b := range site.batteries {
+		if !b.config.ownSoc() {
+			shared = append(shared, b)
+			continue
+		}
+
+		res = append(res, batteryGroup{
+			soc:            b.soc,
+			power:          b.power,
+			prioritySoc:    lo.FromPtrOr(b.config.PrioritySoc, site.prioritySoc),
+			bufferSoc:      lo.FromPtrOr(b.config.BufferSoc, site.bufferSoc),
+			bufferStartSoc: lo.FromPtrOr(b.config.BufferStartSoc, site.bufferStartSoc),
+		})
+	}
+
+	if len(shared) > 0 {
+		soc, _ := aggregateBatteries(shared)
+
+		res = append([]batteryGroup{{
+			soc: soc,
+			power: lo.SumBy(shared, func(b batteryState) float64 {
+				return b.power
+			}),
+			prioritySoc:    site.prioritySoc,
+			bufferSoc:      site.bufferSoc,
+			bufferStartSoc: site.bufferStartSoc,
+		}}, res...)
+	}
+
+	return res
+}
+
 // setBatteryMode sets the battery mode
 func (site *Site) setBatteryMode(batMode api.BatteryMode) {
 	site.batteryMode = batMode
@@ -54,6 +177,7 @@ func (site *Site) requiredBatteryMode(batteryGridChargeActive bool, rate api.Rat
 	var res api.BatteryMode
 	batMode := site.GetBatteryMode()
 	extMode := site.GetBatteryModeExternal()
+	planMode := site.plannedBatteryMode()
 
 	var extModeReset bool
 	if extMode == api.BatteryUnknown {
@@ -77,10 +201,15 @@ func (site *Site) requiredBatteryMode(batteryGridChargeActive bool, rate api.Rat
 		if extMode != batMode {
 			res = extMode
 		}
+	case site.peakShaving:
+		// discharge battery to limit grid import
+		res = mapper(api.BatteryNormal)
 	case batteryGridChargeActive:
 		res = mapper(api.BatteryCharge)
 	case site.dischargeControlActive(rate):
 		res = mapper(api.BatteryHold)
+	case planMode == api.BatteryCharge || planMode == api.BatteryHold:
+		res = mapper(planMode)
 	case batteryModeModified(batMode):
 		res = api.BatteryNormal
 	}
@@ -88,16 +217,110 @@ func (site *Site) requiredBatteryMode(batteryGridChargeActive bool, rate api.Rat
 	return res
 }
 
-// applyBatteryMode applies the mode to each battery
+// updateBatteryPlan plans the battery modes if the battery optimizer is enabled
+func (site *Site) updateBatteryPlan() {
+	if site.batteryPlanner == nil || !site.batteryConfigured() {
+		return
+	}
+
+	capacity, soc := site.batteryPlanState()
+
+	plan, err := site.batteryPlanner.Plan(planner.Battery{
+		Capacity:          capacity,
+		Soc:               soc,
+		Efficiency:        site.BatteryOptimizer.Efficiency,
+		MaxChargePower:    site.BatteryOptimizer.MaxChargePower,
+		MaxDischargePower: site.BatteryOptimizer.MaxDischargePower,
+	})
+	if err != nil {
+		// log once until planning succeeds
+		if !site.batteryPlanFailed {
+			site.log.WARN.Println("battery plan:", err)
+		} else {
+			site.log.DEBUG.Println("battery plan:", err)
+		}
+	}
+	site.batteryPlanFailed = err != nil
+
+	site.Lock()
+	site.batteryPlan = plan
+	site.Unlock()
+}
+
+// batteryPlanState returns the total capacity in kWh and the soc of all batteries with known capacity.
+// Batteries with unknown capacity are not planned but follow the planned mode.
+func (site *Site) batteryPlanState() (float64, float64) {
+	site.RLock()
+	defer site.RUnlock()
+
+	known := lo.Filter(site.batteries, func(b batteryState, _ int) bool {
+		return b.capacity > 0
+	})
+
+	soc, capacity := aggregateBatteries(known)
+	return capacity, soc
+}
+
+// plannedBatteryMode returns the battery mode planned for the current slot
+func (site *Site) plannedBatteryMode() api.BatteryMode {
+	site.RLock()
+	defer site.RUnlock()
+
+	if len(site.batteryPlan) == 0 {
+		return api.BatteryUnknown
+	}
+
+	return site.batteryPlan.SlotAt(site.clock.Now()).Mode
+}
+
+// GetBatteryPlan returns the planned battery modes
+func (site *Site) GetBatteryPlan() (planner.BatteryPlan, error) {
+	if site.batteryPlanner == nil {
+		return nil, errors.New("battery optimizer not enabled")
+	}
+
+	site.RLock()
+	defer site.RUnlock()
+	return site.batteryPlan, nil
+}
+
+// applyBatteryMode applies the mode to each controlled battery.
+// Grid charging uses the configured grid charge power if supported by the battery.
 func (site *Site) applyBatteryMode(mode api.BatteryMode) error {
+	chargePower := site.GetBatteryGridChargePower()
+
+	// batteries sharing the grid charge power
+	var charging int
+	if mode == api.BatteryCharge && chargePower > 0 {
+		for _, dev := range site.batteryMeters {
+			cc, _ := site.batteryConfig(dev)
+			if _, ok := api.Capability[api.BatteryPowerController](dev.Instance()); ok && cc.mode(mode) == api.BatteryCharge {
+				charging++
+			}
+		}
+	}
+
 	for _, dev := range site.batteryMeters {
 		meter := dev.Instance()
 		if _, ok := meter.(api.Meter); !ok {
 			panic("not a meter: battery")
 		}
 
+		cc, _ := site.batteryConfig(dev)
+		batMode := cc.mode(mode)
+		if batMode == api.BatteryUnknown {
+			continue
+		}
+
+		if powerCtrl, ok := api.Capability[api.BatteryPowerController](meter); ok && batMode == api.BatteryCharge && chargePower > 0 {
+			if err := powerCtrl.SetBatteryPower(-chargePower / float64(charging)); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+				return err
+			}
+			continue
+		}
+
 		if batCtrl, ok := meter.(api.BatteryController); ok {
-			if err := batCtrl.SetBatteryMode(mode); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+			if err := batCtrl.SetBatteryMode(batMode); err != nil && !errors.Is(err, api.ErrNotAvailable) {
 				return err
 			}
 		}
@@ -106,6 +329,41 @@ func (site *Site) applyBatteryMode(mode api.BatteryMode) error {
 	return nil
 }
 
+// batteryPowerControllers returns the controlled batteries supporting power setpoints
+func (site *Site) batteryPowerControllers() []api.BatteryPowerController {
+	var res []api.BatteryPowerController
+	for _, dev := range site.batteryMeters {
+		if cc, _ := site.batteryConfig(dev); !cc.controlled() {
+			continue
+		}
+		if powerCtrl, ok := api.Capability[api.BatteryPowerController](dev.Instance()); ok {
+			res = append(res, powerCtrl)
+		}
+	}
+	return res
+}
+
+// updateBatteryPower allows the battery to cover the home power deficit while discharge control is active.
+// The vehicle is still not charged from the battery. Export above the grid export limit is used for charging the battery.
+func (site *Site) updateBatteryPower(homePower float64, rate api.Rate) {
+	if site.GetBatteryMode() != api.BatteryHold || site.GetBatteryModeExternal() != api.BatteryUnknown || !site.dischargeControlActive(rate) {
+		return
+	}
+
+	powerCtrls := site.batteryPowerControllers()
+	if len(powerCtrls) == 0 {
+		return
+	}
+
+	power := max(0, homePower-max(0, site.pvPower)) - site.gridExportExcess
+
+	for _, powerCtrl := range powerCtrls {
+		if err := powerCtrl.SetBatteryPower(power / float64(len(powerCtrls))); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+			site.log.ERROR.Println("battery power:", err)
+		}
+	}
+}
+
 func (site *Site) tariffRates(usage api.TariffUsage) (api.Rates, error) {
 	tariff := site.GetTariff(usage)
 	if tariff == nil || tariff.Type() == api.TariffTypePriceStatic {
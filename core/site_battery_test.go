@@ -4,9 +4,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -115,3 +118,175 @@ func TestExternalBatteryModeChange(t *testing.T) {
 		assert.True(t, site.batteryModeExternalTimer.IsZero())
 	}
 }
+
+func TestRequiredPlannedBatteryMode(t *testing.T) {
+	for _, tc := range []struct {
+		internal, planned, new api.BatteryMode
+	}{
+		{api.BatteryNormal, api.BatteryNormal, api.BatteryUnknown},
+		{api.BatteryNormal, api.BatteryCharge, api.BatteryCharge},
+		{api.BatteryNormal, api.BatteryHold, api.BatteryHold},
+		{api.BatteryCharge, api.BatteryCharge, api.BatteryUnknown}, // no change required
+		{api.BatteryCharge, api.BatteryNormal, api.BatteryNormal},
+		{api.BatteryHold, api.BatteryUnknown, api.BatteryNormal}, // no plan
+	} {
+		t.Logf("%+v", tc)
+
+		clock := clock.NewMock()
+		now := clock.Now()
+
+		site := &Site{
+			log:           util.NewLogger("foo"),
+			clock:         clock,
+			batteryMeters: []config.Device[api.Meter]{nil},
+			batteryMode:   tc.internal,
+		}
+
+		if tc.planned != api.BatteryUnknown {
+			site.batteryPlan = planner.BatteryPlan{{
+				Rate: api.Rate{Start: now.Add(-time.Minute), End: now.Add(time.Hour)},
+				Mode: tc.planned,
+			}}
+		}
+
+		mode := site.requiredBatteryMode(false, api.Rate{})
+		assert.Equal(t, tc.new.String(), mode.String(), "internal mode expected %s got %s", tc.new, mode)
+	}
+}
+
+func TestBatteryPowerControl(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	batCon := api.NewMockBatteryController(ctrl)
+	batPower := api.NewMockBatteryPowerController(ctrl)
+
+	var bat api.Meter = &struct {
+		api.Meter
+		api.BatteryController
+		api.BatteryPowerController
+	}{
+		BatteryController:      batCon,
+		BatteryPowerController: batPower,
+	}
+
+	site := &Site{
+		log:                     util.NewLogger("foo"),
+		batteryMeters:           []config.Device[api.Meter]{config.NewStaticDevice(config.Named{}, bat)},
+		batteryDischargeControl: true,
+		loadpoints:              []*Loadpoint{{status: api.StatusC, mode: api.ModeNow}},
+		pvPower:                 500,
+	}
+
+	// grid charging at max power
+	batCon.EXPECT().SetBatteryMode(api.BatteryCharge).Times(1)
+	assert.NoError(t, site.applyBatteryMode(api.BatteryCharge))
+	ctrl.Finish()
+
+	// grid charging at configured power
+	site.batteryGridChargePower = 2e3
+	batPower.EXPECT().SetBatteryPower(-2e3).Times(1)
+	assert.NoError(t, site.applyBatteryMode(api.BatteryCharge))
+	ctrl.Finish()
+
+	// battery not held, no power setpoint
+	site.batteryMode = api.BatteryNormal
+	site.updateBatteryPower(1500, api.Rate{})
+	ctrl.Finish()
+
+	// battery covers home power deficit only
+	site.batteryMode = api.BatteryHold
+	batPower.EXPECT().SetBatteryPower(1e3).Times(1)
+	site.updateBatteryPower(1500, api.Rate{})
+	ctrl.Finish()
+}
+
+func TestAggregateBatteries(t *testing.T) {
+	for _, tc := range []struct {
+		batteries     []batteryState
+		soc, capacity float64
+	}{
+		{nil, 0, 0},
+		{[]batteryState{{soc: 20, capacity: 10}, {soc: 80, capacity: 5}}, 40, 15},
+		{[]batteryState{{soc: 20}, {soc: 80}}, 50, 0},
+		{[]batteryState{{soc: 20, capacity: 10}, {soc: 80}}, 50, 10}, // capacity partially unknown
+	} {
+		t.Logf("%+v", tc)
+
+		soc, capacity := aggregateBatteries(tc.batteries)
+		assert.Equal(t, tc.soc, soc)
+		assert.Equal(t, tc.capacity, capacity)
+	}
+}
+
+func TestBatteryPlanState(t *testing.T) {
+	site := &Site{
+		batteries: []batteryState{
+			{soc: 20, capacity: 10},
+			{soc: 80}, // capacity unknown
+			{soc: 80, capacity: 5},
+		},
+	}
+
+	capacity, soc := site.batteryPlanState()
+	assert.Equal(t, 15.0, capacity)
+	assert.Equal(t, 40.0, soc)
+}
+
+func TestBatteryGroups(t *testing.T) {
+	site := &Site{
+		prioritySoc: 50,
+		bufferSoc:   80,
+		batteries: []batteryState{
+			{soc: 40, capacity: 10, power: -1e3},
+			{soc: 70, capacity: 10, power: 500, config: BatteryConfig{PrioritySoc: lo.ToPtr(60.0)}},
+			{soc: 60, capacity: 30, power: -2e3, config: BatteryConfig{GridCharge: lo.ToPtr(false)}},
+		},
+	}
+
+	assert.Equal(t, []batteryGroup{
+		{soc: 55, power: -3e3, prioritySoc: 50, bufferSoc: 80},
+		{soc: 70, power: 500, prioritySoc: 60, bufferSoc: 80},
+	}, site.batteryGroups())
+}
+
+func TestBatteryConfigMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	device := func(name string, batCon api.BatteryController) config.Device[api.Meter] {
+		return config.NewStaticDevice(config.Named{Name: name}, api.Meter(&struct {
+			api.Meter
+			api.BatteryController
+		}{
+			BatteryController: batCon,
+		}))
+	}
+
+	batDefault := api.NewMockBatteryController(ctrl)
+	batNoGrid := api.NewMockBatteryController(ctrl)
+	batManual := api.NewMockBatteryController(ctrl)
+
+	site := &Site{
+		log: util.NewLogger("foo"),
+		batteryMeters: []config.Device[api.Meter]{
+			device("default", batDefault),
+			device("noGrid", batNoGrid),
+			device("manual", batManual),
+		},
+		Batteries: map[string]BatteryConfig{
+			"nogrid": {GridCharge: lo.ToPtr(false)},
+			"manual": {Control: lo.ToPtr(false)},
+		},
+	}
+
+	// battery without grid charge is held, uncontrolled battery untouched
+	batDefault.EXPECT().SetBatteryMode(api.BatteryCharge).Times(1)
+	batNoGrid.EXPECT().SetBatteryMode(api.BatteryHold).Times(1)
+	assert.NoError(t, site.applyBatteryMode(api.BatteryCharge))
+	ctrl.Finish()
+
+	// uncontrolled battery keeps discharging
+	batDefault.EXPECT().SetBatteryMode(api.BatteryHold).Times(1)
+	batNoGrid.EXPECT().SetBatteryMode(api.BatteryHold).Times(1)
+	assert.NoError(t, site.applyBatteryMode(api.BatteryHold))
+	ctrl.Finish()
+}
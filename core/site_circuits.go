@@ -1,18 +1,30 @@
 package core
 
 import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/circuit"
 	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/samber/lo"
 )
 
+// evCircuitOverload is the push event sent when a circuit overload is detected
+const evCircuitOverload = "overload"
+
 type circuitStruct struct {
-	Title      string   `json:"title,omitempty"`
-	Icon       string   `json:"icon,omitempty"`
-	Power      float64  `json:"power"`
-	Current    *float64 `json:"current,omitempty"`
-	MaxPower   float64  `json:"maxPower,omitempty"`
-	MaxCurrent float64  `json:"maxCurrent,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Icon       string    `json:"icon,omitempty"`
+	Power      float64   `json:"power"`
+	Current    *float64  `json:"current,omitempty"`
+	Currents   []float64 `json:"currents,omitempty"` // per-phase currents
+	MaxPower   float64   `json:"maxPower,omitempty"`
+	MaxCurrent float64   `json:"maxCurrent,omitempty"`
+	Overloads  int       `json:"overloads,omitempty"` // number of detected overloads
 }
 
 // publishCircuits returns a list of circuit titles
@@ -30,10 +42,17 @@ func (site *Site) publishCircuits() {
 			Power:      instance.GetChargePower(),
 			MaxPower:   instance.GetMaxPower(),
 			MaxCurrent: instance.GetMaxCurrent(),
+			Overloads:  instance.GetOverloads(),
 		}
 
 		if instance.GetMaxCurrent() > 0 {
 			data.Current = lo.EmptyableToPtr(instance.GetMaxPhaseCurrent())
+
+			if pc, ok := instance.(api.PhaseCurrents); ok {
+				if i1, i2, i3, err := pc.Currents(); err == nil {
+					data.Currents = []float64{i1, i2, i3}
+				}
+			}
 		}
 
 		res[c.Config().Name] = data
@@ -41,3 +60,93 @@ func (site *Site) publishCircuits() {
 
 	site.publish(keys.Circuits, res)
 }
+
+// protectCircuits notifies about new circuit overloads and immediately reduces all loadpoints of shedding circuits
+func (site *Site) protectCircuits() {
+	for _, dev := range config.Circuits().Devices() {
+		c := dev.Instance()
+
+		if n := c.GetOverloads(); n > site.overloads[c] {
+			if site.overloads == nil {
+				site.overloads = make(map[api.Circuit]int)
+			}
+			site.overloads[c] = n
+
+			if site.pushChan != nil {
+				site.pushChan <- push.Event{Event: evCircuitOverload, Attributes: overloadAttributes(dev)}
+			}
+		}
+	}
+
+	// lowest priority loadpoints are reduced first
+	loadpoints := slices.Clone(site.loadpoints)
+	slices.SortStableFunc(loadpoints, func(a, b *Loadpoint) int {
+		return cmp.Compare(a.EffectivePriority(), b.EffectivePriority())
+	})
+
+	for _, lp := range loadpoints {
+		lp.shed()
+	}
+}
+
+// overloadAttributes returns the push event attributes of the overloaded circuit
+func overloadAttributes(dev config.Device[api.Circuit]) map[string]any {
+	c := dev.Instance()
+
+	title := deviceProperties(dev).Title
+	if title == "" {
+		title = dev.Config().Name
+	}
+
+	return map[string]any{
+		"circuitName":       dev.Config().Name,
+		"circuitTitle":      title,
+		"circuitPower":      c.GetChargePower(),
+		"circuitCurrent":    c.GetMaxPhaseCurrent(),
+		"circuitMaxPower":   c.GetMaxPower(),
+		"circuitMaxCurrent": c.GetMaxCurrent(),
+		"circuitOverloads":  c.GetOverloads(),
+	}
+}
+
+// pollCircuits starts polling the meters of circuits with fast overload detection until stopped.
+// Circuits due for polling are sent to the returned channel.
+func (site *Site) pollCircuits(stopC <-chan struct{}) <-chan *circuit.Circuit {
+	res := make(chan *circuit.Circuit)
+
+	for _, dev := range config.Circuits().Devices() {
+		c, ok := dev.Instance().(*circuit.Circuit)
+		if !ok || c.PollInterval() == 0 {
+			continue
+		}
+
+		go func() {
+			ticker := time.NewTicker(c.PollInterval())
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					select {
+					case res <- c:
+					case <-stopC:
+						return
+					}
+				case <-stopC:
+					return
+				}
+			}
+		}()
+	}
+
+	return res
+}
+
+// pollCircuit updates the circuit's measurements and sheds load on overload
+func (site *Site) pollCircuit(c *circuit.Circuit) {
+	if err := c.Poll(); err != nil {
+		site.log.ERROR.Println(err)
+	}
+
+	site.protectCircuits()
+}
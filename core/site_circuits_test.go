@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCircuitOverloadEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	circuit := api.NewMockCircuit(ctrl)
+
+	require.NoError(t, config.Circuits().Add(config.NewStaticDevice(config.Named{Name: "garage"}, api.Circuit(circuit))))
+	t.Cleanup(func() { _ = config.Circuits().Delete("garage") })
+
+	pushChan := make(chan push.Event, 1)
+
+	site := &Site{
+		log:      util.NewLogger("foo"),
+		pushChan: pushChan,
+	}
+
+	circuit.EXPECT().GetOverloads().Return(1).Times(2)
+	circuit.EXPECT().GetChargePower().Return(12e3)
+	circuit.EXPECT().GetMaxPhaseCurrent().Return(17.5)
+	circuit.EXPECT().GetMaxPower().Return(11e3)
+	circuit.EXPECT().GetMaxCurrent().Return(16.0)
+	site.protectCircuits()
+
+	require.Len(t, pushChan, 1)
+	ev := <-pushChan
+	assert.Equal(t, evCircuitOverload, ev.Event)
+	assert.Equal(t, map[string]any{
+		"circuitName":       "garage",
+		"circuitTitle":      "garage",
+		"circuitPower":      12e3,
+		"circuitCurrent":    17.5,
+		"circuitMaxPower":   11e3,
+		"circuitMaxCurrent": 16.0,
+		"circuitOverloads":  1,
+	}, ev.Attributes)
+
+	// overload already notified
+	circuit.EXPECT().GetOverloads().Return(1)
+	site.protectCircuits()
+	assert.Empty(t, pushChan)
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+)
+
+// departureInterval is the interval for updating plans from learned departures
+const departureInterval = 24 * time.Hour
+
+// GetDepartures returns the vehicle's departure times learned from its charging sessions
+func (site *Site) GetDepartures(name string) ([]session.Departure, error) {
+	v, err := site.Vehicles().ByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if db.Instance == nil {
+		return nil, errors.New("database offline")
+	}
+
+	now := time.Now()
+
+	sessions, err := session.VehicleSessions(db.Instance, v.Instance().GetTitle(), now.Add(-session.DepartureLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	_, loc := session.DepartureTimezone()
+
+	return sessions.Departures(now, loc), nil
+}
+
+// ApplyDepartures updates the vehicle's repeating plans for its learned departures if a departure soc is configured.
+// Only plans previously created from learned departures are replaced, the user's plans are kept.
+func (site *Site) ApplyDepartures(name string) error {
+	v, err := site.Vehicles().ByName(name)
+	if err != nil {
+		return err
+	}
+
+	soc := v.GetDepartureSoc()
+	if soc == 0 {
+		return nil
+	}
+
+	departures, err := site.GetDepartures(name)
+	if err != nil {
+		return err
+	}
+
+	// keep existing plans until departures have been learned
+	tz, _ := session.DepartureTimezone()
+	learned := session.RepeatingPlans(departures, session.DepartureConfidence, soc, tz)
+	previous := v.GetDeparturePlans()
+	if len(learned) == 0 || reflect.DeepEqual(learned, previous) {
+		return nil
+	}
+
+	plans := slices.DeleteFunc(v.GetRepeatingPlans(), func(p api.RepeatingPlanStruct) bool {
+		return slices.ContainsFunc(previous, func(l api.RepeatingPlanStruct) bool {
+			return reflect.DeepEqual(p, l)
+		})
+	})
+
+	site.log.DEBUG.Printf("%s: repeating plans from learned departures: %v", name, learned)
+
+	if err := v.SetRepeatingPlans(append(plans, learned...)); err != nil {
+		return err
+	}
+
+	v.SetDeparturePlans(learned)
+
+	return nil
+}
+
+// updateDepartures periodically updates the repeating plans of vehicles from their learned departures
+func (site *Site) updateDepartures() {
+	if db.Instance == nil || time.Since(site.departuresUpdated) < departureInterval {
+		return
+	}
+
+	site.departuresUpdated = time.Now()
+
+	for _, v := range site.Vehicles().Settings() {
+		if err := site.ApplyDepartures(v.Name()); err != nil {
+			site.log.ERROR.Printf("%s: departures: %v", v.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/samber/lo"
+)
+
+const (
+	gridExportTolerance  = 100              // W below the export limit still considered as export at the limit
+	gridExportProbe      = 500              // W offered to loadpoints while export is at the limit, revealing pv curtailed by the inverter
+	gridExportProbeDelay = time.Minute      // export at the limit before probing
+	gridExportProbePause = 10 * time.Minute // no probing after the probe did not reveal curtailed pv
+	pvLimitStep          = 500              // W the pv limit is raised by at most while export is below the limit
+	pvCurtailedPersist   = 15 * time.Minute // curtailed energy persisted at most once per interval while curtailing
+)
+
+// maxGridExport returns the grid export limit if configured
+func (site *Site) maxGridExport() (float64, bool) {
+	if site.getMaxGridExport != nil {
+		res, err := site.getMaxGridExport()
+		if err == nil {
+			return res, true
+		}
+		site.log.WARN.Printf("get max grid export: %v", err)
+	}
+
+	if site.MaxGridExport != nil {
+		return *site.MaxGridExport, true
+	}
+
+	return 0, false
+}
+
+// updateGridExport enforces the grid export limit by curtailing pv if supported.
+// It returns the surplus power hidden by the export limit which loadpoints may additionally consume.
+func (site *Site) updateGridExport() float64 {
+	limit, ok := site.maxGridExport()
+	if !ok {
+		site.gridExportExcess = 0
+		site.exportAtLimit = time.Time{}
+		site.exportProbing = false
+		if site.pvLimited {
+			site.removePvLimit()
+		}
+		return 0
+	}
+
+	site.publish(keys.MaxGridExport, limit)
+
+	// export above the limit, negative if below
+	excess := -site.gridPower - limit
+	site.gridExportExcess = max(0, excess)
+
+	if excess > 0 {
+		site.log.DEBUG.Printf("grid export: %.0fW above limit of %.0fW", excess, limit)
+	}
+
+	var curtailed, surplus float64
+
+	if limiters, powers := site.pvLimiters(); len(limiters) > 0 {
+		site.updatePvLimit(limiters, powers, excess)
+
+		if site.pvLimited {
+			curtailed = max(0, site.pvUnlimitedPower-lo.Sum(powers))
+			surplus = curtailed
+		}
+	} else {
+		surplus = site.exportProbe(excess)
+	}
+
+	site.curtailedEnergy.AddPower(curtailed)
+	site.persistCurtailedEnergy(curtailed)
+
+	site.publish(keys.PvCurtailedPower, curtailed)
+	site.publish(keys.PvCurtailedEnergy, site.curtailedEnergy.Accumulated)
+
+	return surplus
+}
+
+// exportProbe returns the surplus offered to loadpoints for revealing pv curtailed by the inverter itself.
+// Probing starts once the export has stayed at the limit and pauses if the export falls below the limit while probing.
+func (site *Site) exportProbe(excess float64) float64 {
+	now := site.clock.Now()
+
+	// export below the limit, pv not curtailed
+	if excess <= -gridExportTolerance {
+		if site.exportProbing {
+			site.log.DEBUG.Println("grid export: no curtailed pv, pausing probe")
+			site.exportProbePause = now.Add(gridExportProbePause)
+		}
+
+		site.exportAtLimit = time.Time{}
+		site.exportProbing = false
+
+		return 0
+	}
+
+	if site.exportAtLimit.IsZero() {
+		site.exportAtLimit = now
+	}
+
+	if !site.exportProbing && now.Sub(site.exportAtLimit) >= gridExportProbeDelay && !now.Before(site.exportProbePause) {
+		site.log.DEBUG.Println("grid export: at limit, probing for curtailed pv")
+		site.exportProbing = true
+	}
+
+	if site.exportProbing {
+		return gridExportProbe
+	}
+
+	return 0
+}
+
+// persistCurtailedEnergy persists the curtailed energy once curtailment ends and periodically while curtailing
+func (site *Site) persistCurtailedEnergy(curtailed float64) {
+	now := site.clock.Now()
+
+	energy := site.curtailedEnergy.Accumulated
+	if energy == site.curtailedPersisted || curtailed > 0 && now.Sub(site.curtailedPersistedAt) < pvCurtailedPersist {
+		return
+	}
+
+	settings.SetFloat(keys.PvCurtailedEnergy, energy)
+	site.curtailedPersisted = energy
+	site.curtailedPersistedAt = now
+}
+
+// pvLimiters returns the pv meters supporting power limitation with their current power
+func (site *Site) pvLimiters() ([]api.PowerLimiter, []float64) {
+	var (
+		res    []api.PowerLimiter
+		powers []float64
+	)
+
+	for i, dev := range site.pvMeters {
+		if pl, ok := api.Capability[api.PowerLimiter](dev.Instance()); ok && i < len(site.pvPowers) {
+			res = append(res, pl)
+			powers = append(powers, max(0, site.pvPowers[i]))
+		}
+	}
+
+	return res, powers
+}
+
+// updatePvLimit curtails pv by the export above the limit. While curtailed, the limit is raised in steps as long as the
+// export stays below the limit, revealing the pv power before curtailment without exceeding the export limit.
+// The curtailment is released once the pv meters fall clearly below the limit.
+func (site *Site) updatePvLimit(limiters []api.PowerLimiter, powers []float64, excess float64) {
+	// pv power of the limited meters only
+	power := lo.Sum(powers)
+
+	switch {
+	case !site.pvLimited:
+		if excess <= 0 {
+			return
+		}
+		site.pvUnlimitedPower = power
+
+	// pv at the limit produces at least its current power
+	case power >= site.pvLimit-gridExportTolerance:
+		site.pvUnlimitedPower = max(site.pvUnlimitedPower, power)
+
+	// pv just below the limit is not curtailed
+	case power >= site.pvLimit-pvLimitStep || excess > 0:
+		site.pvUnlimitedPower = power
+
+	default:
+		site.removePvLimit()
+		return
+	}
+
+	// lower the limit by the export above or raise it by the export below the limit
+	switch {
+	case excess > 0:
+		site.setPvLimit(limiters, powers, max(0, power-excess))
+	case excess < -gridExportTolerance:
+		site.setPvLimit(limiters, powers, site.pvLimit+min(-excess, pvLimitStep))
+	}
+}
+
+// setPvLimit distributes the limit across the pv meters according to their current power
+func (site *Site) setPvLimit(limiters []api.PowerLimiter, powers []float64, limit float64) {
+	total := lo.Sum(powers)
+
+	var errs []error
+	for i, pl := range limiters {
+		share := 1 / float64(len(limiters))
+		if total > 0 {
+			share = powers[i] / total
+		}
+
+		if err := pl.SetPowerLimit(limit * share); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		site.log.ERROR.Println("pv limit:", err)
+		return
+	}
+
+	if !site.pvLimited {
+		site.log.DEBUG.Printf("pv limit: curtailing pv of %.0fW", site.pvUnlimitedPower)
+	}
+
+	site.log.DEBUG.Printf("pv limit: %.0fW", limit)
+	site.pvLimited = true
+	site.pvLimit = limit
+}
+
+// removePvLimit removes the power limit of all pv meters
+func (site *Site) removePvLimit() {
+	limiters, _ := site.pvLimiters()
+
+	var errs []error
+	for _, pl := range limiters {
+		if err := pl.SetPowerLimit(-1); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		site.log.ERROR.Println("pv limit:", err)
+		return
+	}
+
+	site.log.DEBUG.Println("pv limit: removed")
+	site.pvLimited = false
+	site.pvLimit = 0
+	site.pvUnlimitedPower = 0
+}
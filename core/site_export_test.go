@@ -0,0 +1,123 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGridExportCurtailment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pl := api.NewMockPowerLimiter(ctrl)
+
+	pv := &struct {
+		api.Meter
+		api.PowerLimiter
+	}{
+		PowerLimiter: pl,
+	}
+
+	clock := clock.NewMock()
+
+	site := &Site{
+		log:           util.NewLogger("foo"),
+		clock:         clock,
+		MaxGridExport: lo.ToPtr(1e3),
+		pvMeters: []config.Device[api.Meter]{
+			config.NewStaticDevice(config.Named{}, api.Meter(pv)),
+			config.NewStaticDevice(config.Named{}, api.Meter(api.NewMockMeter(ctrl))), // not limited
+		},
+		curtailedEnergy: &meterEnergy{clock: clock},
+	}
+
+	update := func(pvPower, gridPower float64) float64 {
+		site.pvPower = pvPower + 1e3
+		site.pvPowers = []float64{pvPower, 1e3}
+		site.gridPower = gridPower
+		return site.updateGridExport()
+	}
+
+	// export above limit
+	pl.EXPECT().SetPowerLimit(3e3).Times(1)
+	assert.Equal(t, 0.0, update(5e3, -3e3))
+	assert.True(t, site.pvLimited)
+	assert.Equal(t, 2e3, site.gridExportExcess)
+	ctrl.Finish()
+
+	// curtailed power of the limited meter offered to loadpoints
+	assert.Equal(t, 2e3, update(3e3, -1e3))
+	ctrl.Finish()
+
+	// loadpoints consuming, limit raised in steps
+	pl.EXPECT().SetPowerLimit(3.5e3).Times(1)
+	assert.Equal(t, 2e3, update(3e3, 500))
+	ctrl.Finish()
+
+	// pv at the raised limit
+	pl.EXPECT().SetPowerLimit(4e3).Times(1)
+	assert.Equal(t, 1.5e3, update(3.5e3, 0))
+	ctrl.Finish()
+
+	// pv just below the limit, no longer curtailed
+	pl.EXPECT().SetPowerLimit(4.5e3).Times(1)
+	assert.Equal(t, 0.0, update(3.7e3, -200))
+	assert.True(t, site.pvLimited)
+	ctrl.Finish()
+
+	// pv clearly below the limit, limit removed
+	pl.EXPECT().SetPowerLimit(-1.0).Times(1)
+	assert.Equal(t, 0.0, update(3.7e3, -200))
+	assert.False(t, site.pvLimited)
+	ctrl.Finish()
+
+	// limit kept at the export limit without lifting it
+	pl.EXPECT().SetPowerLimit(3e3).Times(1)
+	assert.Equal(t, 0.0, update(5e3, -3e3))
+	for range 10 {
+		clock.Add(5 * time.Minute)
+		assert.Equal(t, 2e3, update(3e3, -1e3))
+	}
+	assert.True(t, site.pvLimited)
+	ctrl.Finish()
+}
+
+func TestGridExportProbe(t *testing.T) {
+	clock := clock.NewMock()
+
+	site := &Site{
+		log:             util.NewLogger("foo"),
+		clock:           clock,
+		MaxGridExport:   lo.ToPtr(0.0),
+		curtailedEnergy: &meterEnergy{clock: clock},
+	}
+
+	// export at the limit, pv may be curtailed by the inverter
+	site.gridPower = -50
+	assert.Equal(t, 0.0, site.updateGridExport())
+	clock.Add(gridExportProbeDelay)
+	assert.Equal(t, float64(gridExportProbe), site.updateGridExport())
+
+	// import, probe did not reveal curtailed pv
+	site.gridPower = 500
+	assert.Equal(t, 0.0, site.updateGridExport())
+
+	// probing paused
+	site.gridPower = -50
+	assert.Equal(t, 0.0, site.updateGridExport())
+	clock.Add(gridExportProbeDelay)
+	assert.Equal(t, 0.0, site.updateGridExport())
+	clock.Add(gridExportProbePause)
+	assert.Equal(t, float64(gridExportProbe), site.updateGridExport())
+
+	// no limit
+	site.MaxGridExport = nil
+	site.gridPower = -5e3
+	assert.Equal(t, 0.0, site.updateGridExport())
+}
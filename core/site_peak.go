@@ -0,0 +1,136 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/peak"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// peakShavingRelease is the import below the limit required for releasing peak shaving
+const peakShavingRelease = 500 // W
+
+// peakTarget returns the max quarter-hour average import
+func (site *Site) peakTarget() float64 {
+	if site.PeakShaving.Target > 0 {
+		return site.PeakShaving.Target
+	}
+	return max(site.peakTracker.Peak().Power, site.PeakShaving.MinTarget)
+}
+
+// updatePeakShaving tracks the grid import and limits the loadpoints' power to keep the
+// quarter-hour average import below the target
+func (site *Site) updatePeakShaving(totalChargePower float64) {
+	if site.peakTracker == nil {
+		return
+	}
+
+	if site.peakTracker.Update(site.gridPower) {
+		p := site.peakTracker.Peak()
+		site.log.DEBUG.Printf("peak shaving: monthly peak %.0fW", p.Power)
+
+		if err := settings.SetJson(keys.PeakShaving, p); err != nil {
+			site.log.ERROR.Println("peak shaving:", err)
+		}
+	}
+
+	target := site.peakTarget()
+	limit := site.peakTracker.Limit(target)
+
+	site.limitPeakImport(limit, totalChargePower)
+
+	status := peak.Status{
+		Peak:    site.peakTracker.Peak(),
+		Average: site.peakTracker.Average(),
+		Target:  target,
+		Limit:   limit,
+	}
+
+	site.Lock()
+	site.peakStatus = status
+	site.Unlock()
+
+	site.publish(keys.PeakShaving, status)
+}
+
+// limitPeakImport starts peak shaving once the import exceeds the limit. Since the circuit limit keeps the import
+// at the limit, peak shaving is only released once the import has fallen below the limit by the release margin.
+func (site *Site) limitPeakImport(limit, totalChargePower float64) {
+	// import without battery discharge
+	power := site.gridPower + max(0, site.batteryPower)
+
+	active := power > limit
+	if site.peakShaving {
+		active = power > limit-peakShavingRelease
+	}
+
+	if active != site.peakShaving {
+		if active {
+			site.log.INFO.Printf("peak shaving: import %.0fW above limit of %.0fW", power, limit)
+		} else {
+			site.log.INFO.Println("peak shaving: released")
+		}
+	}
+
+	site.peakShaving = active
+	if site.peakShaving {
+		site.log.DEBUG.Printf("peak shaving: import limited to %.0fW", limit)
+	}
+
+	site.limitCircuitPower(limit, totalChargePower)
+}
+
+// limitCircuitPower limits the root circuit to the remaining import power while peak shaving is active
+// and restores the circuit's limit afterwards
+func (site *Site) limitCircuitPower(limit, totalChargePower float64) {
+	if site.circuit == nil {
+		return
+	}
+
+	// circuit limit changed externally, e.g. by hems
+	if configured := site.circuit.GetMaxPower(); site.peakCircuitLimit == 0 || configured != site.peakCircuitLimit {
+		site.peakCircuitPower = configured
+	}
+
+	if !site.peakShaving {
+		site.restoreCircuitPower()
+		return
+	}
+
+	// circuits without meter only measure the loadpoints
+	power := limit
+	if !site.circuit.HasMeter() {
+		power = limit - (site.gridPower - totalChargePower)
+	}
+
+	if site.peakCircuitPower > 0 {
+		power = min(power, site.peakCircuitPower)
+	}
+
+	// zero disables the circuit's power limit
+	if power < 1 {
+		if site.peakCircuitLimit != 1 {
+			site.log.WARN.Println("peak shaving: import limit reached, charging blocked")
+		}
+		power = 1
+	}
+
+	site.circuit.SetMaxPower(power)
+	site.peakCircuitLimit = power
+}
+
+// restoreCircuitPower restores the root circuit's limit once peak shaving has ended
+func (site *Site) restoreCircuitPower() {
+	if site.circuit == nil || site.peakCircuitLimit == 0 {
+		return
+	}
+
+	site.circuit.SetMaxPower(site.peakCircuitPower)
+	site.peakCircuitLimit = 0
+}
+
+// GetPeakShaving returns the peak shaving state
+func (site *Site) GetPeakShaving() peak.Status {
+	site.RLock()
+	defer site.RUnlock()
+	return site.peakStatus
+}
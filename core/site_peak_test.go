@@ -0,0 +1,131 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPeakShavingCircuitPower(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	circuit := api.NewMockCircuit(ctrl)
+
+	site := &Site{
+		log:       util.NewLogger("foo"),
+		circuit:   circuit,
+		gridPower: 5e3,
+	}
+
+	// not peak shaving, circuit limit unchanged
+	circuit.EXPECT().GetMaxPower().Return(10e3)
+	site.limitCircuitPower(4e3, 3e3)
+
+	site.peakShaving = true
+
+	// circuit meter measures the grid import
+	circuit.EXPECT().GetMaxPower().Return(10e3)
+	circuit.EXPECT().HasMeter().Return(true)
+	circuit.EXPECT().SetMaxPower(4e3)
+	site.limitCircuitPower(4e3, 3e3)
+
+	// loadpoints only, 2kW house consumption
+	circuit.EXPECT().GetMaxPower().Return(4e3)
+	circuit.EXPECT().HasMeter().Return(false)
+	circuit.EXPECT().SetMaxPower(2e3)
+	site.limitCircuitPower(4e3, 3e3)
+
+	// configured circuit limit
+	circuit.EXPECT().GetMaxPower().Return(2e3)
+	circuit.EXPECT().HasMeter().Return(true)
+	circuit.EXPECT().SetMaxPower(10e3)
+	site.limitCircuitPower(20e3, 3e3)
+
+	// circuit limit changed externally
+	circuit.EXPECT().GetMaxPower().Return(8e3)
+	circuit.EXPECT().HasMeter().Return(true)
+	circuit.EXPECT().SetMaxPower(8e3)
+	site.limitCircuitPower(20e3, 3e3)
+
+	// budget exhausted
+	circuit.EXPECT().GetMaxPower().Return(8e3)
+	circuit.EXPECT().HasMeter().Return(false)
+	circuit.EXPECT().SetMaxPower(1.0)
+	site.limitCircuitPower(1e3, 0)
+
+	// peak shaving ended, circuit limit restored
+	site.peakShaving = false
+	circuit.EXPECT().GetMaxPower().Return(1.0)
+	circuit.EXPECT().SetMaxPower(8e3)
+	site.limitCircuitPower(20e3, 0)
+
+	circuit.EXPECT().GetMaxPower().Return(8e3)
+	site.limitCircuitPower(20e3, 0)
+}
+
+func TestPeakShavingHysteresis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	circuit := api.NewMockCircuit(ctrl)
+
+	maxPower := 10e3
+	circuit.EXPECT().GetMaxPower().DoAndReturn(func() float64 { return maxPower }).AnyTimes()
+	circuit.EXPECT().HasMeter().Return(true).AnyTimes()
+	circuit.EXPECT().SetMaxPower(gomock.Any()).Do(func(power float64) { maxPower = power }).AnyTimes()
+
+	site := &Site{
+		log:     util.NewLogger("foo"),
+		circuit: circuit,
+	}
+
+	for i, tc := range []struct {
+		gridPower, batteryPower float64
+		peakShaving             bool
+		maxPower                float64
+	}{
+		{3e3, 0, false, 10e3},
+		{6e3, 0, true, 4e3},     // import above limit
+		{4e3, 0, true, 4e3},     // import kept at limit by the circuit
+		{3.8e3, 0, true, 4e3},   // within release margin
+		{3e3, 1e3, true, 4e3},   // battery discharging
+		{3e3, 0, false, 10e3},   // released
+		{3.8e3, 0, false, 10e3}, // not started below limit
+	} {
+		t.Logf("%d. %+v", i+1, tc)
+
+		site.gridPower = tc.gridPower
+		site.batteryPower = tc.batteryPower
+		site.limitPeakImport(4e3, 2e3)
+
+		assert.Equal(t, tc.peakShaving, site.peakShaving, "peak shaving")
+		assert.Equal(t, tc.maxPower, maxPower, "circuit max power")
+	}
+}
+
+func TestPeakShavingBatteryMode(t *testing.T) {
+	for _, tc := range []struct {
+		internal    api.BatteryMode
+		gridCharge  bool
+		peakShaving bool
+		new         api.BatteryMode
+	}{
+		{api.BatteryHold, false, true, api.BatteryNormal},
+		{api.BatteryCharge, true, true, api.BatteryNormal},
+		{api.BatteryNormal, true, true, api.BatteryUnknown}, // no change required
+		{api.BatteryNormal, true, false, api.BatteryCharge},
+	} {
+		t.Logf("%+v", tc)
+
+		site := &Site{
+			log:           util.NewLogger("foo"),
+			batteryMeters: []config.Device[api.Meter]{nil},
+			batteryMode:   tc.internal,
+			peakShaving:   tc.peakShaving,
+		}
+
+		mode := site.requiredBatteryMode(tc.gridCharge, api.Rate{})
+		assert.Equal(t, tc.new.String(), mode.String())
+	}
+}
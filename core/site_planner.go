@@ -0,0 +1,45 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/core/planner"
+)
+
+// sitePlanner plans the charging plans of all loadpoints jointly within the root circuit's limits
+type sitePlanner struct {
+	site    *Site
+	planner *planner.SitePlanner
+}
+
+// Plan returns the loadpoint's share of the combined power plan of all loadpoints for the given request
+func (sp *sitePlanner) Plan(lp *Loadpoint, req planner.Request) planner.PowerPlan {
+	reqs := []planner.Request{req}
+
+	for _, other := range sp.site.loadpoints {
+		if other == lp {
+			continue
+		}
+
+		if r, ok := other.activePlanRequest(); ok {
+			reqs = append(reqs, r)
+		}
+	}
+
+	return sp.planner.PlanPower(reqs, sp.maxPower())[0]
+}
+
+// maxPower returns the root circuit's power limit or 0 if unlimited
+func (sp *sitePlanner) maxPower() float64 {
+	c := sp.site.circuit
+	if c == nil {
+		return 0
+	}
+
+	res := c.GetMaxPower()
+	if current := c.GetMaxCurrent(); current > 0 {
+		if power := 3 * Voltage * current; res == 0 || power < res {
+			res = power
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/schedule"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// scheduleRefreshInterval is the interval for reloading calendar feeds
+const scheduleRefreshInterval = time.Hour
+
+// GetSchedules returns the scheduler configuration
+func (site *Site) GetSchedules() schedule.Config {
+	return site.scheduler.Config()
+}
+
+// SetSchedules sets the scheduler configuration
+func (site *Site) SetSchedules(config schedule.Config) error {
+	site.log.DEBUG.Printf("set schedules: %d rules, %d calendars", len(config.Rules), len(config.Calendars))
+
+	if err := site.scheduler.SetConfig(config); err != nil {
+		return err
+	}
+
+	// reload calendars in the background
+	select {
+	case site.scheduleRefresh <- struct{}{}:
+	default:
+	}
+
+	return settings.SetJson(keys.Schedules, config)
+}
+
+// refreshSchedules loads the scheduler's calendar feeds and reloads them periodically or on request until stopped
+func (site *Site) refreshSchedules(stopC <-chan struct{}) {
+	ticker := time.NewTicker(scheduleRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := site.scheduler.Refresh(); err != nil {
+			site.log.WARN.Println("schedules:", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-site.scheduleRefresh:
+		case <-stopC:
+			return
+		}
+	}
+}
+
+// applySchedules applies the settings of all schedules triggered since the last update
+func (site *Site) applySchedules() {
+	now := site.clock.Now()
+
+	// don't apply past schedules on startup
+	if site.scheduled.IsZero() {
+		site.scheduled = now
+		return
+	}
+
+	for _, t := range site.scheduler.Due(site.scheduled, now) {
+		site.log.DEBUG.Printf("schedule triggered at %v: %+v", t.Time.Round(time.Second).Local(), t.Action)
+		site.applyScheduleAction(t.Action)
+	}
+
+	site.scheduled = now
+}
+
+// applyScheduleAction applies the action's settings to the site and its loadpoints
+func (site *Site) applyScheduleAction(action schedule.Action) {
+	for id, lp := range site.loadpoints {
+		if action.Loadpoint != 0 && action.Loadpoint != id+1 {
+			continue
+		}
+
+		if action.Mode != "" {
+			lp.SetMode(action.Mode)
+		}
+		if action.LimitSoc != nil {
+			lp.SetLimitSoc(*action.LimitSoc)
+		}
+		if action.MaxCurrent != nil {
+			if err := lp.SetMaxCurrent(*action.MaxCurrent); err != nil {
+				lp.log.ERROR.Println("schedule:", err)
+			}
+		}
+		if action.Priority != nil {
+			lp.SetPriority(*action.Priority)
+		}
+	}
+
+	if action.BufferSoc != nil {
+		if err := site.SetBufferSoc(*action.BufferSoc); err != nil {
+			site.log.ERROR.Println("schedule:", err)
+		}
+	}
+	if action.BufferStartSoc != nil {
+		if err := site.SetBufferStartSoc(*action.BufferStartSoc); err != nil {
+			site.log.ERROR.Println("schedule:", err)
+		}
+	}
+	if action.PrioritySoc != nil {
+		if err := site.SetPrioritySoc(*action.PrioritySoc); err != nil {
+			site.log.ERROR.Println("schedule:", err)
+		}
+	}
+	if action.BatteryDischargeControl != nil {
+		if err := site.SetBatteryDischargeControl(*action.BatteryDischargeControl); err != nil {
+			site.log.ERROR.Println("schedule:", err)
+		}
+	}
+}
@@ -102,6 +102,7 @@ func (site *Site) publishTariffs(greenShareHome float64, greenShareLoadpoints fl
 		Co2     api.Rates     `json:"co2,omitempty"`
 		FeedIn  api.Rates     `json:"feedin,omitempty"`
 		Grid    api.Rates     `json:"grid,omitempty"`
+		Home    api.Rates     `json:"home,omitempty"`
 		Planner api.Rates     `json:"planner,omitempty"`
 		Solar   *solarDetails `json:"solar,omitempty"`
 	}{
@@ -111,6 +112,10 @@ func (site *Site) publishTariffs(greenShareHome float64, greenShareLoadpoints fl
 		Grid:    tariff.Forecast(site.GetTariff(api.TariffUsageGrid)),
 	}
 
+	if home, err := site.GetHomeForecast(); err == nil {
+		fc.Home = home
+	}
+
 	// calculate adjusted solar forecast
 	if solar := timestampSeries(tariff.Forecast(site.GetTariff(api.TariffUsageSolar))); len(solar) > 0 {
 		fc.Solar = lo.ToPtr(site.solarDetails(solar))
@@ -0,0 +1,136 @@
+package soc
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+const (
+	curveBuckets   = 20 // resolution of the learned curve in steps of relative power (5%)
+	curveMaxWeight = 5  // limit weight of previous sessions so that the curve keeps adapting
+	curveMaxRatio  = 0.95
+)
+
+// CurvePoint maps a relative charge power to the soc at which it has been observed
+type CurvePoint struct {
+	Ratio float64 `json:"ratio"` // charge power relative to max power
+	Soc   float64 `json:"soc"`   // soc in %
+	Count int     `json:"count"` // number of sessions contributing to this point
+}
+
+// ChargingCurve is the learned charge power taper of a vehicle
+type ChargingCurve struct {
+	Points   []CurvePoint `json:"points"`
+	Sessions int          `json:"sessions"`
+}
+
+// Valid returns true if the curve contains enough points for soc lookup
+func (c ChargingCurve) Valid() bool {
+	return len(c.Points) >= 2
+}
+
+// Soc returns the soc for given relative charge power. Ratios above the learned
+// range are not part of the taper and return false.
+func (c ChargingCurve) Soc(ratio float64) (float64, bool) {
+	if !c.Valid() || ratio > c.Points[0].Ratio {
+		return 0, false
+	}
+
+	// enforce monotonically increasing soc for decreasing power
+	socs := make([]float64, len(c.Points))
+	for i, p := range c.Points {
+		socs[i] = p.Soc
+		if i > 0 {
+			socs[i] = max(socs[i], socs[i-1])
+		}
+	}
+
+	for i := 1; i < len(c.Points); i++ {
+		hi, lo := c.Points[i-1], c.Points[i]
+		if ratio >= lo.Ratio {
+			f := (hi.Ratio - ratio) / (hi.Ratio - lo.Ratio)
+			return socs[i-1] + f*(socs[i]-socs[i-1]), true
+		}
+	}
+
+	// below learned range
+	return socs[len(socs)-1], true
+}
+
+// Ratio returns the relative charge power for given soc. Below the learned taper full power is assumed.
+func (c ChargingCurve) Ratio(soc float64) float64 {
+	if !c.Valid() || soc <= c.Points[0].Soc {
+		return 1
+	}
+
+	for i := 1; i < len(c.Points); i++ {
+		hi, lo := c.Points[i-1], c.Points[i]
+		if soc <= lo.Soc {
+			if lo.Soc <= hi.Soc {
+				return hi.Ratio
+			}
+			f := (soc - hi.Soc) / (lo.Soc - hi.Soc)
+			return hi.Ratio - f*(hi.Ratio-lo.Ratio)
+		}
+	}
+
+	// above learned range
+	return c.Points[len(c.Points)-1].Ratio
+}
+
+// learn merges the taper of a single session into the curve. The soc of each
+// measurement is derived from the anchor soc and energy and the energy charged in between.
+func (c *ChargingCurve) learn(history []PowerMeasurement, maxPower, anchorSoc, anchorEnergy, capacity float64) int {
+	if maxPower <= 0 || capacity <= 0 || len(history) == 0 {
+		return 0
+	}
+
+	virtualCapacity := capacity * 1e3 / ChargeEfficiency
+
+	sum := make(map[int]float64)
+	count := make(map[int]int)
+
+	for _, m := range history {
+		ratio := m.Power / maxPower
+		if ratio <= 0 || ratio >= curveMaxRatio {
+			continue
+		}
+
+		soc := anchorSoc - (anchorEnergy-m.Energy)/virtualCapacity*100
+		if soc < 0 {
+			continue
+		}
+
+		idx := int(ratio * curveBuckets)
+		sum[idx] += soc
+		count[idx]++
+	}
+
+	for idx, n := range count {
+		ratio := (float64(idx) + 0.5) / curveBuckets
+		soc := sum[idx] / float64(n)
+
+		if i := slices.IndexFunc(c.Points, func(p CurvePoint) bool {
+			return math.Abs(p.Ratio-ratio) < 1e-6
+		}); i >= 0 {
+			p := &c.Points[i]
+			w := float64(min(p.Count, curveMaxWeight))
+			p.Soc = (p.Soc*w + soc) / (w + 1)
+			p.Count++
+		} else {
+			c.Points = append(c.Points, CurvePoint{Ratio: ratio, Soc: soc, Count: 1})
+		}
+	}
+
+	if len(count) > 0 {
+		c.Sessions++
+	}
+
+	// sort by decreasing power
+	slices.SortFunc(c.Points, func(a, b CurvePoint) int {
+		return cmp.Compare(b.Ratio, a.Ratio)
+	})
+
+	return len(count)
+}
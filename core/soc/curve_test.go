@@ -0,0 +1,76 @@
+package soc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargingCurve_Soc(t *testing.T) {
+	curve := ChargingCurve{
+		Points: []CurvePoint{
+			{Ratio: 0.875, Soc: 70},
+			{Ratio: 0.625, Soc: 80},
+			{Ratio: 0.375, Soc: 90},
+		},
+	}
+
+	_, ok := curve.Soc(0.95)
+	assert.False(t, ok, "above taper")
+
+	soc, ok := curve.Soc(0.75)
+	require.True(t, ok)
+	assert.InDelta(t, 75, soc, 1e-6)
+
+	soc, ok = curve.Soc(0.1)
+	require.True(t, ok)
+	assert.Equal(t, 90.0, soc, "below learned range")
+
+	assert.False(t, ChargingCurve{}.Valid())
+}
+
+func TestChargingCurve_Learn(t *testing.T) {
+	var curve ChargingCurve
+
+	// 10 kWh capacity, 1.11 kWh virtual energy per 10% soc
+	start := time.Now()
+	history := []PowerMeasurement{
+		{Timestamp: start, Power: 10000, Energy: 0},
+		{Timestamp: start.Add(time.Hour), Power: 8000, Energy: 1000 / ChargeEfficiency},
+		{Timestamp: start.Add(2 * time.Hour), Power: 4000, Energy: 2000 / ChargeEfficiency},
+	}
+
+	n := curve.learn(history, 10000, 100, 2000/ChargeEfficiency, 10)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 1, curve.Sessions)
+	require.Len(t, curve.Points, 2)
+
+	assert.Equal(t, 0.825, curve.Points[0].Ratio)
+	assert.InDelta(t, 90, curve.Points[0].Soc, 1e-6)
+	assert.Equal(t, 0.425, curve.Points[1].Ratio)
+	assert.InDelta(t, 100, curve.Points[1].Soc, 1e-6)
+
+	// second session merges into existing points
+	n = curve.learn(history, 10000, 90, 2000/ChargeEfficiency, 10)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2, curve.Sessions)
+	assert.InDelta(t, 85, curve.Points[0].Soc, 1e-6)
+	assert.Equal(t, 2, curve.Points[0].Count)
+}
+
+func TestChargingCurve_Ratio(t *testing.T) {
+	curve := ChargingCurve{
+		Points: []CurvePoint{
+			{Ratio: 0.875, Soc: 70},
+			{Ratio: 0.625, Soc: 80},
+			{Ratio: 0.375, Soc: 90},
+		},
+	}
+
+	assert.Equal(t, 1.0, curve.Ratio(50), "above taper")
+	assert.InDelta(t, 0.75, curve.Ratio(75), 1e-6)
+	assert.Equal(t, 0.375, curve.Ratio(95), "above learned range")
+	assert.Equal(t, 1.0, ChargingCurve{}.Ratio(95))
+}
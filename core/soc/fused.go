@@ -0,0 +1,196 @@
+package soc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// FusedEstimator provides a continuous vehicle soc for vehicles without soc api.
+// The session start soc is back-calculated from the soc at which the learned
+// charging taper is observed and the energy charged since plug-in.
+type FusedEstimator struct {
+	sync.Mutex
+	log   *util.Logger
+	speed *SpeedEstimator
+
+	virtualCapacity float64  // vehicle capacity in Wh taking efficiency into account
+	startSoc        *float64 // back-calculated soc at session start
+	vehicleSoc      float64  // estimated vehicle soc
+	full            bool     // vehicle full has been applied as calibration point
+}
+
+// FusedEstimatorState is the persistable state of the current plug-in session
+type FusedEstimatorState struct {
+	StartSoc   *float64 `json:"startSoc,omitempty"`
+	VehicleSoc float64  `json:"vehicleSoc"`
+	Full       bool     `json:"full"`
+}
+
+// NewFusedEstimator creates new fused estimator for given speed estimator and vehicle capacity in kWh
+func NewFusedEstimator(log *util.Logger, speed *SpeedEstimator, capacity float64) *FusedEstimator {
+	return &FusedEstimator{
+		log:             log,
+		speed:           speed,
+		virtualCapacity: capacity * 1e3 / ChargeEfficiency,
+	}
+}
+
+// Reset forgets the session start soc
+func (s *FusedEstimator) Reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.startSoc = nil
+	s.vehicleSoc = 0
+	s.full = false
+}
+
+// State returns the persistable state of the current plug-in session
+func (s *FusedEstimator) State() FusedEstimatorState {
+	s.Lock()
+	defer s.Unlock()
+
+	return FusedEstimatorState{
+		StartSoc:   s.startSoc,
+		VehicleSoc: s.vehicleSoc,
+		Full:       s.full,
+	}
+}
+
+// Restore resumes a previously persisted plug-in session
+func (s *FusedEstimator) Restore(state FusedEstimatorState) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.startSoc = state.StartSoc
+	s.vehicleSoc = state.VehicleSoc
+	s.full = state.Full
+}
+
+// Soc returns the estimated soc for given session charged energy in Wh.
+// The result is only valid once the charging taper has been observed.
+func (s *FusedEstimator) Soc(chargedEnergy float64) (float64, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.virtualCapacity <= 0 {
+		return 0, false
+	}
+
+	chargedSoc := max(chargedEnergy, 0) / s.virtualCapacity * 100
+
+	// vehicle finished charging with full battery
+	if _, full := s.speed.VehicleFinished(); full && !s.full {
+		s.full = true
+
+		startSoc := max(100-chargedSoc, 0)
+		s.startSoc = &startSoc
+
+		s.log.DEBUG.Printf("fused soc: session start soc %.1f%% (vehicle full, charged: %.0fWh)", startSoc, chargedEnergy)
+	}
+
+	if s.startSoc == nil {
+		taperSoc, ok := s.speed.TaperSoc()
+		if !ok {
+			return 0, false
+		}
+
+		startSoc := max(taperSoc-chargedSoc, 0)
+		s.startSoc = &startSoc
+
+		s.log.DEBUG.Printf("fused soc: session start soc %.1f%% (taper: %.1f%%, charged: %.0fWh)", startSoc, taperSoc, chargedEnergy)
+	}
+
+	s.vehicleSoc = min(*s.startSoc+chargedSoc, 100)
+
+	return s.vehicleSoc, true
+}
+
+// Calibrate anchors the session start soc at the given actual soc and session charged energy in Wh
+func (s *FusedEstimator) Calibrate(soc, chargedEnergy float64) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.virtualCapacity <= 0 {
+		return
+	}
+
+	startSoc := max(soc-max(chargedEnergy, 0)/s.virtualCapacity*100, 0)
+	s.startSoc = &startSoc
+	s.vehicleSoc = soc
+
+	s.log.DEBUG.Printf("fused soc: session start soc %.1f%% (calibrated: %.1f%%, charged: %.0fWh)", startSoc, soc, chargedEnergy)
+}
+
+// PlanDuration returns the charge duration for the given energy in kWh at given max power,
+// taking the learned charging taper into account. The duration is only available once the soc is known.
+func (s *FusedEstimator) PlanDuration(energy, maxPower float64) (time.Duration, bool) {
+	if s.virtualCapacity <= 0 || maxPower <= 0 {
+		return 0, false
+	}
+
+	curve := s.speed.Curve()
+
+	// taper is relative to the vehicle's max power
+	vehicleMaxPower := s.speed.MaxPower()
+	if vehicleMaxPower <= 0 {
+		vehicleMaxPower = maxPower
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.startSoc == nil {
+		return 0, false
+	}
+
+	soc := s.vehicleSoc
+	targetSoc := min(soc+max(energy, 0)*1e3/s.virtualCapacity*100, 100)
+
+	var hours float64
+	for soc < targetSoc {
+		step := min(1, targetSoc-soc)
+
+		power := min(maxPower, vehicleMaxPower*curve.Ratio(soc+step/2))
+		if power <= 0 {
+			break
+		}
+
+		hours += step / 100 * s.virtualCapacity / power
+		soc += step
+	}
+
+	return time.Duration(float64(time.Hour) * hours).Round(time.Second), true
+}
+
+// RemainingChargeEnergy returns the remaining charge energy in kWh
+func (s *FusedEstimator) RemainingChargeEnergy(targetSoc int) float64 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.remainingChargeEnergy(targetSoc)
+}
+
+// remainingChargeEnergy returns the remaining charge energy in kWh (no mutex)
+func (s *FusedEstimator) remainingChargeEnergy(targetSoc int) float64 {
+	percentRemaining := float64(targetSoc) - s.vehicleSoc
+	if percentRemaining <= 0 || s.virtualCapacity <= 0 {
+		return 0
+	}
+
+	return percentRemaining / 100 * s.virtualCapacity / 1e3
+}
+
+// RemainingChargeDuration returns the remaining duration at given charge power
+func (s *FusedEstimator) RemainingChargeDuration(targetSoc int, chargePower float64) time.Duration {
+	if chargePower <= 0 {
+		return 0
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return time.Duration(float64(time.Hour) * 1e3 * s.remainingChargeEnergy(targetSoc) / chargePower).Round(time.Second)
+}
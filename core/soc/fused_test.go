@@ -0,0 +1,159 @@
+package soc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFusedEstimator(t *testing.T) {
+	log := util.NewLogger("test")
+
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+
+	se := NewSpeedEstimator(log, config)
+	se.curve = ChargingCurve{
+		Points: []CurvePoint{
+			{Ratio: 0.875, Soc: 70},
+			{Ratio: 0.625, Soc: 80},
+		},
+	}
+
+	// 9 kWh virtual capacity
+	s := NewFusedEstimator(log, se, 9*ChargeEfficiency)
+
+	_, ok := s.Soc(1000)
+	assert.False(t, ok, "taper not observed yet")
+
+	// taper observed at 70% after 4.5 kWh
+	se.estimationActive = true
+	se.estimatedSoc = 70
+
+	soc, ok := s.Soc(4500)
+	require.True(t, ok)
+	assert.InDelta(t, 70, soc, 1e-6)
+
+	// continuous soc from charged energy, independent of speed estimator
+	se.estimationActive = false
+
+	soc, ok = s.Soc(5400)
+	require.True(t, ok)
+	assert.InDelta(t, 80, soc, 1e-6)
+
+	assert.InDelta(t, 0.9, s.RemainingChargeEnergy(90), 1e-6)
+	assert.Equal(t, 18*time.Minute, s.RemainingChargeDuration(90, 3000))
+
+	s.Reset()
+	_, ok = s.Soc(5400)
+	assert.False(t, ok)
+}
+
+func TestFusedEstimatorCalibrate(t *testing.T) {
+	log := util.NewLogger("test")
+
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+
+	se := NewSpeedEstimator(log, config)
+
+	// 9 kWh virtual capacity
+	s := NewFusedEstimator(log, se, 9*ChargeEfficiency)
+
+	// actual soc confirmed after 0.9 kWh, no taper required
+	s.Calibrate(50, 900)
+
+	soc, ok := s.Soc(1800)
+	require.True(t, ok)
+	assert.InDelta(t, 60, soc, 1e-6)
+}
+
+func TestFusedEstimatorRestore(t *testing.T) {
+	log := util.NewLogger("test")
+
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+
+	se := NewSpeedEstimator(log, config)
+
+	// 9 kWh virtual capacity
+	s := NewFusedEstimator(log, se, 9*ChargeEfficiency)
+	s.Calibrate(50, 900)
+
+	// back-calculated start soc survives restart without taper
+	restored := NewFusedEstimator(log, NewSpeedEstimator(log, config), 9*ChargeEfficiency)
+	restored.Restore(s.State())
+
+	soc, ok := restored.Soc(1800)
+	require.True(t, ok)
+	assert.InDelta(t, 60, soc, 1e-6)
+}
+
+func TestFusedEstimatorPlanDuration(t *testing.T) {
+	log := util.NewLogger("test")
+
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+
+	se := NewSpeedEstimator(log, config)
+
+	// 9 kWh virtual capacity
+	s := NewFusedEstimator(log, se, 9*ChargeEfficiency)
+
+	// unknown soc
+	_, ok := s.PlanDuration(0.9, 9000)
+	assert.False(t, ok)
+
+	// known soc
+	s.Calibrate(70, 0)
+
+	d, ok := s.PlanDuration(0.9, 9000)
+	require.True(t, ok)
+	assert.Equal(t, 6*time.Minute, d)
+
+	d, _ = s.PlanDuration(0, 9000)
+	assert.Equal(t, time.Duration(0), d)
+
+	// learned taper slows down charging
+	se.curve = ChargingCurve{
+		Points: []CurvePoint{
+			{Ratio: 0.875, Soc: 70},
+			{Ratio: 0.625, Soc: 80},
+		},
+	}
+
+	d, _ = s.PlanDuration(0.9, 9000)
+	assert.Greater(t, d, 7*time.Minute)
+	assert.Less(t, d, 9*time.Minute)
+}
+
+func TestFusedEstimatorVehicleFull(t *testing.T) {
+	log := util.NewLogger("test")
+
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+
+	se := NewSpeedEstimator(log, config)
+
+	// 9 kWh virtual capacity
+	s := NewFusedEstimator(log, se, 9*ChargeEfficiency)
+
+	// anchored too low by the taper
+	s.Calibrate(80, 0)
+
+	// vehicle full after 0.9 kWh
+	se.vehicleFinished = true
+	se.vehicleFull = true
+
+	soc, ok := s.Soc(900)
+	require.True(t, ok)
+	assert.Equal(t, 100.0, soc)
+
+	s.Reset()
+	se.Reset()
+	_, ok = s.Soc(900)
+	assert.False(t, ok)
+}
@@ -0,0 +1,70 @@
+package soc
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+)
+
+// ReplaySample is a recorded charge power sample
+type ReplaySample struct {
+	Timestamp time.Time
+	Power     float64 // W
+	Offered   float64 // W offered by the loadpoint, 0 if unknown
+}
+
+// ReplayResult is the outcome of replaying a recorded session
+type ReplayResult struct {
+	Stopped         bool          // target soc reached
+	Stop            time.Time     // time charging would have been stopped
+	Duration        time.Duration // charging duration until stop or end of recording
+	Energy          float64       // Wh charged until stop or end of recording
+	EstimationStart time.Time     // time soc estimation became active
+	EstimatedSoc    float64       // estimated soc at stop or end of recording
+}
+
+// Replay feeds the recorded samples through a speed estimator using a fake clock.
+// Replay ends when the estimator reports the target soc as reached.
+func Replay(log *util.Logger, config ChargingSpeedConfig, samples []ReplaySample) ReplayResult {
+	var res ReplayResult
+	if len(samples) == 0 {
+		return res
+	}
+
+	config.Enabled = true
+
+	clock := clock.NewMock()
+	clock.Set(samples[0].Timestamp)
+
+	se := NewSpeedEstimator(log, config)
+	se.clock = clock
+	se.StartCharging()
+
+	var prev ReplaySample
+	for i, s := range samples {
+		clock.Set(s.Timestamp)
+
+		if i > 0 {
+			res.Energy += (prev.Power + s.Power) / 2 * s.Timestamp.Sub(prev.Timestamp).Hours()
+		}
+		prev = s
+
+		se.UpdatePower(s.Power, s.Offered)
+
+		if res.EstimationStart.IsZero() && se.IsEstimationActive() {
+			res.EstimationStart = s.Timestamp
+		}
+
+		if se.IsTargetReached() {
+			res.Stopped = true
+			res.Stop = s.Timestamp
+			break
+		}
+	}
+
+	res.Duration = prev.Timestamp.Sub(samples[0].Timestamp)
+	res.EstimatedSoc = se.GetEstimatedSoc()
+
+	return res
+}
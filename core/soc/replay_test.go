@@ -0,0 +1,43 @@
+package soc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	log := util.NewLogger("test")
+
+	// one hour at full power, then tapering
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var samples []ReplaySample
+	for i := range 120 {
+		power := 11000.0
+		if i >= 60 {
+			power = 11000 - float64(i-60)*150
+		}
+
+		samples = append(samples, ReplaySample{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Power:     power,
+		})
+	}
+
+	config := DefaultChargingSpeedConfig()
+
+	res := Replay(log, config, samples)
+	assert.True(t, res.Stopped)
+	assert.False(t, res.EstimationStart.IsZero())
+	assert.True(t, res.Stop.After(res.EstimationStart))
+	assert.GreaterOrEqual(t, res.EstimatedSoc, float64(config.TargetSoc))
+
+	// higher threshold stops later
+	config.ReductionThreshold = 0.3
+
+	later := Replay(log, config, samples)
+	assert.True(t, later.Stop.After(res.Stop))
+	assert.Greater(t, later.Energy, res.Energy)
+}
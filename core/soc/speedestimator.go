@@ -1,18 +1,45 @@
 package soc
 
 import (
+	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/settings"
 	"github.com/evcc-io/evcc/util"
 )
 
 // PowerMeasurement represents a single power measurement with timestamp
 type PowerMeasurement struct {
-	Timestamp time.Time
-	Power     float64 // Watts
+	Timestamp time.Time `json:"timestamp"`
+	Power     float64   `json:"power"`   // Watts
+	Energy    float64   `json:"energy"`  // Wh charged since session start
+	Offered   float64   `json:"offered"` // Watts offered by the loadpoint, 0 if unknown
+}
+
+// limited returns true if the measured power is limited by the offered power rather than by the vehicle
+func (m PowerMeasurement) limited(threshold float64) bool {
+	return m.Offered > 0 && m.Power >= m.Offered*(1-threshold)
+}
+
+// SpeedEstimatorState is the persistable state of the current plug-in session
+type SpeedEstimatorState struct {
+	PowerHistory     []PowerMeasurement `json:"powerHistory"`
+	MaxPower         float64            `json:"maxPower"`
+	MaxPowerTime     time.Time          `json:"maxPowerTime"`
+	MaxPowerLimited  bool               `json:"maxPowerLimited"`
+	ChargingStarted  time.Time          `json:"chargingStarted"`
+	ReferenceStart   time.Time          `json:"referenceStart"`
+	EstimationActive bool               `json:"estimationActive"`
+	EstimatedSoc     float64            `json:"estimatedSoc"`
+	TargetReached    bool               `json:"targetReached"`
+	VehicleFinished  bool               `json:"vehicleFinished"`
+	VehicleFull      bool               `json:"vehicleFull"`
+	Learned          bool               `json:"learned"`
 }
 
 // ChargingSpeedConfig holds configuration for charging speed-based SoC estimation
@@ -26,23 +53,34 @@ type ChargingSpeedConfig struct {
 	HistoryRetention      time.Duration `mapstructure:"historyRetention"`      // How long to keep power history (default: 2h)
 	StabilityWindow       time.Duration `mapstructure:"stabilityWindow"`       // Window to check for stable power reduction (default: 5min)
 	MinPowerForEstimation float64       `mapstructure:"minPowerForEstimation"` // Minimum power to consider for estimation (default: 1000W)
+	TrickleDuration       time.Duration `mapstructure:"trickleDuration"`       // Charging below min power for this duration marks the vehicle full (default: 15min)
+	Capacity              float64       `mapstructure:"capacity"`              // Vehicle battery capacity in kWh, required for learning the charging curve
+
+	TemperatureReference   float64 `mapstructure:"temperatureReference"`   // Ambient temperature above which the taper is not affected (default: 20°C)
+	TemperatureCoefficient float64 `mapstructure:"temperatureCoefficient"` // SoC % the taper moves down per °C below reference (default: 1.0)
 }
 
 // DefaultChargingSpeedConfig returns default configuration
 func DefaultChargingSpeedConfig() ChargingSpeedConfig {
 	return ChargingSpeedConfig{
-		Enabled:               false,
-		TargetSoc:             80,
-		MaxPowerWindow:        10 * time.Minute,
-		ReductionThreshold:    0.15, // 15% reduction
-		MinChargingTime:       15 * time.Minute,
-		SampleInterval:        30 * time.Second,
-		HistoryRetention:      2 * time.Hour,
-		StabilityWindow:       5 * time.Minute,
-		MinPowerForEstimation: 1000, // 1kW minimum
+		Enabled:                false,
+		TargetSoc:              80,
+		MaxPowerWindow:         10 * time.Minute,
+		ReductionThreshold:     0.15, // 15% reduction
+		MinChargingTime:        15 * time.Minute,
+		SampleInterval:         30 * time.Second,
+		HistoryRetention:       2 * time.Hour,
+		StabilityWindow:        5 * time.Minute,
+		MinPowerForEstimation:  1000, // 1kW minimum
+		TrickleDuration:        15 * time.Minute,
+		TemperatureReference:   20,
+		TemperatureCoefficient: 1.0,
 	}
 }
 
+// maxTemperatureOffset limits the taper correction for cold batteries
+const maxTemperatureOffset = 30
+
 // SpeedEstimator estimates SoC based on charging speed reduction patterns
 type SpeedEstimator struct {
 	sync.RWMutex
@@ -50,17 +88,34 @@ type SpeedEstimator struct {
 	clock  clock.Clock
 	config ChargingSpeedConfig
 
+	// Learned charging curve
+	store settings.Settings // vehicle settings for persisting the learned curve
+	curve ChargingCurve
+
+	// Ambient temperature
+	temperatureG func() (float64, error)
+	temperature  *float64 // last known ambient temperature
+
 	// Power history tracking
 	powerHistory    []PowerMeasurement
 	maxPower        float64   // Maximum observed charging power
 	maxPowerTime    time.Time // When max power was observed
+	maxPowerLimited bool      // Max power was limited by offered power and may still increase
 	chargingStarted time.Time // When current charging session started
+	referenceStart  time.Time // When the max power reference started, i.e. charging start or last phase or current limit change
 
 	// Estimation state
 	estimationActive bool      // Whether SoC estimation is currently active
 	estimatedSoc     float64   // Current estimated SoC
 	targetReached    bool      // Whether target SoC has been reached
 	lastSample       time.Time // Last time we sampled power
+	resumed          bool      // Charging resumed after pause, don't integrate energy across the pause
+	learned          bool      // Session has been merged into the learned curve
+
+	// End of charge detection
+	vehicleFinished bool      // Vehicle stopped charging on its own
+	vehicleFull     bool      // Vehicle stopped charging after the taper, battery is considered full
+	trickleStart    time.Time // When power dropped below min power while not limited by the loadpoint
 }
 
 // NewSpeedEstimator creates a new charging speed-based SoC estimator
@@ -76,35 +131,289 @@ func NewSpeedEstimator(log *util.Logger, config ChargingSpeedConfig) *SpeedEstim
 	}
 }
 
-// StartCharging initializes the estimator for a new charging session
-func (se *SpeedEstimator) StartCharging() {
+// Reset clears the state of the current plug-in session
+func (se *SpeedEstimator) Reset() {
 	se.Lock()
 	defer se.Unlock()
 
-	se.chargingStarted = se.clock.Now()
+	se.chargingStarted = time.Time{}
+	se.referenceStart = time.Time{}
 	se.powerHistory = nil
 	se.maxPower = 0
 	se.maxPowerTime = time.Time{}
+	se.maxPowerLimited = false
 	se.estimationActive = false
 	se.estimatedSoc = 0
 	se.targetReached = false
 	se.lastSample = time.Time{}
+	se.resumed = false
+	se.learned = false
+	se.vehicleFinished = false
+	se.vehicleFull = false
+	se.trickleStart = time.Time{}
+}
+
+// StartCharging initializes the estimator for a new charging session or resumes
+// the existing session after charging has been paused
+func (se *SpeedEstimator) StartCharging() {
+	se.Lock()
+	defer se.Unlock()
+
+	if !se.chargingStarted.IsZero() {
+		se.resumed = len(se.powerHistory) > 0
+		se.log.DEBUG.Println("speed estimator: charging session resumed")
+		return
+	}
 
+	se.chargingStarted = se.clock.Now()
+	se.referenceStart = se.chargingStarted
 	se.log.DEBUG.Println("speed estimator: charging session started")
 }
 
-// StopCharging stops the estimation and clears state
-func (se *SpeedEstimator) StopCharging() {
+// ResetReference restarts max power detection, e.g. after phase switching or current limit changes.
+// Estimated SoC and target state are kept.
+func (se *SpeedEstimator) ResetReference(reason string) {
 	se.Lock()
 	defer se.Unlock()
 
+	if se.chargingStarted.IsZero() {
+		return
+	}
+
+	se.maxPower = 0
+	se.maxPowerTime = time.Time{}
+	se.maxPowerLimited = false
 	se.estimationActive = false
-	se.targetReached = false
+	se.referenceStart = se.clock.Now()
+
+	se.log.DEBUG.Printf("speed estimator: max power reset (%s)", reason)
+}
+
+// SetTemperature assigns the ambient temperature getter
+func (se *SpeedEstimator) SetTemperature(temperatureG func() (float64, error)) {
+	se.Lock()
+	defer se.Unlock()
+	se.temperatureG = temperatureG
+}
+
+// StopCharging pauses the estimation. State is kept until Reset.
+func (se *SpeedEstimator) StopCharging() {
+	se.Lock()
+	defer se.Unlock()
+
 	se.log.DEBUG.Println("speed estimator: charging session stopped")
 }
 
-// UpdatePower adds a new power measurement and updates SoC estimation
-func (se *SpeedEstimator) UpdatePower(power float64) {
+// State returns the persistable state of the current plug-in session
+func (se *SpeedEstimator) State() SpeedEstimatorState {
+	se.RLock()
+	defer se.RUnlock()
+
+	return SpeedEstimatorState{
+		PowerHistory:     slices.Clone(se.powerHistory),
+		MaxPower:         se.maxPower,
+		MaxPowerTime:     se.maxPowerTime,
+		MaxPowerLimited:  se.maxPowerLimited,
+		ChargingStarted:  se.chargingStarted,
+		ReferenceStart:   se.referenceStart,
+		EstimationActive: se.estimationActive,
+		EstimatedSoc:     se.estimatedSoc,
+		TargetReached:    se.targetReached,
+		VehicleFinished:  se.vehicleFinished,
+		VehicleFull:      se.vehicleFull,
+		Learned:          se.learned,
+	}
+}
+
+// Restore resumes a previously persisted plug-in session. Outdated state is ignored.
+func (se *SpeedEstimator) Restore(state SpeedEstimatorState) bool {
+	se.Lock()
+	defer se.Unlock()
+
+	n := len(state.PowerHistory)
+	if n == 0 || se.clock.Since(state.PowerHistory[n-1].Timestamp) > se.config.HistoryRetention {
+		return false
+	}
+
+	se.powerHistory = state.PowerHistory
+	se.maxPower = state.MaxPower
+	se.maxPowerTime = state.MaxPowerTime
+	se.maxPowerLimited = state.MaxPowerLimited
+	se.chargingStarted = state.ChargingStarted
+	se.referenceStart = state.ReferenceStart
+	if se.referenceStart.IsZero() {
+		se.referenceStart = se.chargingStarted
+	}
+	se.estimationActive = state.EstimationActive
+	se.estimatedSoc = state.EstimatedSoc
+	se.targetReached = state.TargetReached
+	se.vehicleFinished = state.VehicleFinished
+	se.vehicleFull = state.VehicleFull
+	se.learned = state.Learned
+	se.lastSample = time.Time{}
+	se.resumed = true
+
+	se.log.DEBUG.Printf("speed estimator: restored session (%d measurements, max power: %.0fW)", n, se.maxPower)
+
+	return true
+}
+
+// SetStore assigns the vehicle settings and restores the learned charging curve
+func (se *SpeedEstimator) SetStore(store settings.Settings) {
+	se.Lock()
+	defer se.Unlock()
+
+	se.store = store
+
+	var curve ChargingCurve
+	if err := store.Json(keys.SpeedEstimatorCurve, &curve); err == nil {
+		se.curve = curve
+		se.log.DEBUG.Printf("speed estimator: restored charging curve (%d points, %d sessions)", len(curve.Points), curve.Sessions)
+	}
+
+	// runtime settings override the vehicle configuration
+	if v, err := store.Int(keys.SpeedEstimatorTargetSoc); err == nil && v > 0 {
+		se.config.TargetSoc = int(v)
+	}
+	if v, err := store.Float(keys.SpeedEstimatorReductionThreshold); err == nil && v > 0 {
+		se.config.ReductionThreshold = v
+	}
+	if v, err := store.Float(keys.SpeedEstimatorMinPower); err == nil && v > 0 {
+		se.config.MinPowerForEstimation = v
+	}
+}
+
+// Config returns the effective configuration
+func (se *SpeedEstimator) Config() ChargingSpeedConfig {
+	se.RLock()
+	defer se.RUnlock()
+	return se.config
+}
+
+// SetTargetSoc sets the target soc and stores it for the vehicle
+func (se *SpeedEstimator) SetTargetSoc(soc int) error {
+	if soc <= 0 || soc > 100 {
+		return fmt.Errorf("invalid target soc: %d", soc)
+	}
+
+	se.Lock()
+	defer se.Unlock()
+
+	se.config.TargetSoc = soc
+	se.targetReached = se.estimationActive && se.estimatedSoc >= float64(soc)
+
+	if se.store != nil {
+		se.store.SetInt(keys.SpeedEstimatorTargetSoc, int64(soc))
+	}
+
+	return nil
+}
+
+// SetReductionThreshold sets the power reduction threshold and stores it for the vehicle
+func (se *SpeedEstimator) SetReductionThreshold(threshold float64) error {
+	if threshold <= 0 || threshold >= 1 {
+		return fmt.Errorf("invalid reduction threshold: %.2f", threshold)
+	}
+
+	se.Lock()
+	defer se.Unlock()
+
+	se.config.ReductionThreshold = threshold
+
+	if se.store != nil {
+		se.store.SetFloat(keys.SpeedEstimatorReductionThreshold, threshold)
+	}
+
+	return nil
+}
+
+// SetMinPowerForEstimation sets the minimum max power required for estimation and stores it for the vehicle
+func (se *SpeedEstimator) SetMinPowerForEstimation(power float64) error {
+	if power <= 0 {
+		return fmt.Errorf("invalid min power: %.0f", power)
+	}
+
+	se.Lock()
+	defer se.Unlock()
+
+	se.config.MinPowerForEstimation = power
+
+	if se.store != nil {
+		se.store.SetFloat(keys.SpeedEstimatorMinPower, power)
+	}
+
+	return nil
+}
+
+// ResetCurve forgets the learned charging curve of the vehicle
+func (se *SpeedEstimator) ResetCurve() {
+	se.Lock()
+	defer se.Unlock()
+
+	se.curve = ChargingCurve{}
+
+	if se.store != nil {
+		if err := se.store.SetJson(keys.SpeedEstimatorCurve, se.curve); err != nil {
+			se.log.ERROR.Printf("speed estimator: store charging curve: %v", err)
+		}
+	}
+
+	se.log.INFO.Println("speed estimator: charging curve reset")
+}
+
+// Curve returns the learned charging curve
+func (se *SpeedEstimator) Curve() ChargingCurve {
+	se.RLock()
+	defer se.RUnlock()
+	return se.curve
+}
+
+// Calibrate anchors the current session at the given soc and merges its power taper into the learned curve.
+// The given soc replaces the current estimate.
+func (se *SpeedEstimator) Calibrate(soc float64) bool {
+	se.Lock()
+	defer se.Unlock()
+
+	res := se.calibrate(soc)
+
+	se.estimatedSoc = soc
+	se.targetReached = soc >= float64(se.config.TargetSoc)
+
+	return res
+}
+
+// calibrate learns the charging curve from the current session (no mutex)
+func (se *SpeedEstimator) calibrate(soc float64) bool {
+	if se.config.Capacity <= 0 {
+		se.log.DEBUG.Println("speed estimator: cannot learn charging curve without vehicle capacity")
+		return false
+	}
+
+	if len(se.powerHistory) == 0 {
+		return false
+	}
+
+	anchorEnergy := se.powerHistory[len(se.powerHistory)-1].Energy
+	n := se.curve.learn(se.vehicleLimited(), se.maxPower, soc, anchorEnergy, se.config.Capacity)
+	if n == 0 {
+		return false
+	}
+
+	se.learned = true
+	se.log.INFO.Printf("speed estimator: learned charging curve at %.0f%% (%d points, %d sessions)", soc, len(se.curve.Points), se.curve.Sessions)
+
+	if se.store != nil {
+		if err := se.store.SetJson(keys.SpeedEstimatorCurve, se.curve); err != nil {
+			se.log.ERROR.Printf("speed estimator: store charging curve: %v", err)
+		}
+	}
+
+	return true
+}
+
+// EndSession is called when the vehicle is disconnected. A session that has not been learned yet,
+// e.g. because charging was stopped at the target soc, is merged into the learned curve anchored at the estimated soc.
+func (se *SpeedEstimator) EndSession() {
 	if !se.config.Enabled {
 		return
 	}
@@ -112,29 +421,156 @@ func (se *SpeedEstimator) UpdatePower(power float64) {
 	se.Lock()
 	defer se.Unlock()
 
+	if se.learned || !se.estimationActive || se.estimatedSoc <= 0 {
+		return
+	}
+
+	se.calibrate(se.estimatedSoc)
+}
+
+// FinishCharging is called when the vehicle stopped charging on its own while the charger was still enabled.
+// If power has collapsed after the taper has been observed, the vehicle is full and the session is recorded
+// as 100% calibration point. If the vehicle stopped without taper, it has reached its own charge limit.
+func (se *SpeedEstimator) FinishCharging(power float64) {
+	if !se.config.Enabled {
+		return
+	}
+
+	se.Lock()
+	defer se.Unlock()
+
+	if se.maxPower < se.config.MinPowerForEstimation || power >= se.config.MinPowerForEstimation {
+		return
+	}
+
+	se.finish(se.estimationActive || se.tapered(), "charging stopped")
+}
+
+// tapered returns true if the vehicle reduced power below the reduction threshold before stopping
+func (se *SpeedEstimator) tapered() bool {
+	threshold := se.maxPower * (1 - se.config.ReductionThreshold)
+	return slices.ContainsFunc(se.vehicleLimited(), func(m PowerMeasurement) bool {
+		return m.Power >= se.config.MinPowerForEstimation && m.Power <= threshold
+	})
+}
+
+// finish marks the session as finished by the vehicle (no mutex)
+func (se *SpeedEstimator) finish(full bool, reason string) {
+	if se.vehicleFinished {
+		return
+	}
+
+	se.vehicleFinished = true
+	se.vehicleFull = full
+
+	if !full {
+		se.log.INFO.Printf("speed estimator: vehicle reached its own limit (%s)", reason)
+		return
+	}
+
+	se.log.INFO.Printf("speed estimator: vehicle full (%s)", reason)
+	se.calibrate(100)
+
+	se.estimatedSoc = 100
+	se.targetReached = true
+}
+
+// detectTrickle marks the vehicle full if it keeps charging below min power for the trickle duration (no mutex)
+func (se *SpeedEstimator) detectTrickle(now time.Time, power float64, limited bool) {
+	if limited || power >= se.config.MinPowerForEstimation || se.maxPower < se.config.MinPowerForEstimation {
+		se.trickleStart = time.Time{}
+
+		// vehicle limit has been raised
+		if se.vehicleFinished && !se.vehicleFull && !limited && power >= se.config.MinPowerForEstimation {
+			se.vehicleFinished = false
+			se.log.DEBUG.Println("speed estimator: vehicle resumed charging")
+		}
+
+		return
+	}
+
+	if se.trickleStart.IsZero() {
+		se.trickleStart = now
+	}
+
+	if se.config.TrickleDuration > 0 && now.Sub(se.trickleStart) >= se.config.TrickleDuration {
+		se.finish(true, "trickle charging")
+	}
+}
+
+// UpdatePower adds a new power measurement and updates SoC estimation.
+// Offered power is the power the loadpoint allows from its current limit and active phases.
+// Reductions caused by the loadpoint (e.g. PV mode or circuit limits) are not treated as taper.
+func (se *SpeedEstimator) UpdatePower(power, offeredPower float64) {
+	if !se.config.Enabled {
+		return
+	}
+
+	// read ambient temperature outside the lock
+	temperature := se.readTemperature()
+
+	se.Lock()
+	defer se.Unlock()
+
+	if temperature != nil {
+		se.temperature = temperature
+	}
+
 	now := se.clock.Now()
 
 	// Check if we should sample (rate limiting)
 	if !se.lastSample.IsZero() && now.Sub(se.lastSample) < se.config.SampleInterval {
 		return
 	}
+
+	// Estimator created while already charging
+	if se.chargingStarted.IsZero() {
+		se.chargingStarted = now
+		se.referenceStart = now
+	}
+
+	// Integrate charged energy
+	var energy float64
+	if n := len(se.powerHistory); n > 0 {
+		prev := se.powerHistory[n-1]
+		energy = prev.Energy
+		if !se.resumed {
+			energy += (prev.Power + power) / 2 * now.Sub(prev.Timestamp).Hours()
+		}
+	}
+	se.resumed = false
+
 	se.lastSample = now
 
 	// Add measurement to history
 	measurement := PowerMeasurement{
 		Timestamp: now,
 		Power:     power,
+		Energy:    energy,
+		Offered:   max(offeredPower, 0),
 	}
 	se.powerHistory = append(se.powerHistory, measurement)
 
 	// Clean old measurements
 	se.cleanOldMeasurements()
 
-	// Update max power if this is higher and within the max power window
-	if power > se.maxPower && (se.maxPowerTime.IsZero() || now.Sub(se.chargingStarted) <= se.config.MaxPowerWindow) {
+	// Update max power if this is higher and within the max power window.
+	// If max power was limited by the loadpoint it is not the vehicle's max power and may still increase.
+	limited := measurement.limited(se.config.ReductionThreshold)
+	if power > se.maxPower && (se.maxPowerTime.IsZero() || se.maxPowerLimited || now.Sub(se.referenceStart) <= se.config.MaxPowerWindow) {
 		se.maxPower = power
 		se.maxPowerTime = now
-		se.log.DEBUG.Printf("speed estimator: new max power %.0fW", power)
+		se.maxPowerLimited = limited
+		se.log.DEBUG.Printf("speed estimator: new max power %.0fW (limited: %t)", power, limited)
+	}
+
+	// Long trickle charging indicates a full battery
+	se.detectTrickle(now, power, limited)
+
+	// Power limited by the loadpoint does not tell anything about the vehicle's taper
+	if limited {
+		se.log.DEBUG.Printf("speed estimator: power %.0fW limited by offered power %.0fW", power, offeredPower)
+		return
 	}
 
 	// Check if we can start estimation
@@ -149,10 +585,18 @@ func (se *SpeedEstimator) UpdatePower(power float64) {
 	}
 }
 
+// vehicleLimited returns the measurements of the current max power reference where power was limited
+// by the vehicle rather than the loadpoint
+func (se *SpeedEstimator) vehicleLimited() []PowerMeasurement {
+	return slices.DeleteFunc(slices.Clone(se.powerHistory), func(m PowerMeasurement) bool {
+		return m.Timestamp.Before(se.referenceStart) || m.limited(se.config.ReductionThreshold)
+	})
+}
+
 // canStartEstimation checks if conditions are met to start SoC estimation
 func (se *SpeedEstimator) canStartEstimation(now time.Time, currentPower float64) bool {
-	// Must have been charging for minimum time
-	if now.Sub(se.chargingStarted) < se.config.MinChargingTime {
+	// Must have been charging for minimum time since max power detection started
+	if now.Sub(se.referenceStart) < se.config.MinChargingTime {
 		return false
 	}
 
@@ -177,7 +621,7 @@ func (se *SpeedEstimator) hasStablePowerReduction(now time.Time, _ float64) bool
 
 	var recentMeasurements []PowerMeasurement
 	for _, m := range se.powerHistory {
-		if m.Timestamp.After(stabilityStart) {
+		if m.Timestamp.After(stabilityStart) && !m.Timestamp.Before(se.referenceStart) {
 			recentMeasurements = append(recentMeasurements, m)
 		}
 	}
@@ -186,10 +630,10 @@ func (se *SpeedEstimator) hasStablePowerReduction(now time.Time, _ float64) bool
 		return false
 	}
 
-	// Check that power has been consistently below threshold
+	// Check that power has been consistently below threshold and not limited by the loadpoint
 	threshold := se.maxPower * (1 - se.config.ReductionThreshold)
 	for _, m := range recentMeasurements {
-		if m.Power > threshold {
+		if m.Power > threshold || m.limited(se.config.ReductionThreshold) {
 			return false
 		}
 	}
@@ -197,19 +641,56 @@ func (se *SpeedEstimator) hasStablePowerReduction(now time.Time, _ float64) bool
 	return true
 }
 
+// readTemperature returns the current ambient temperature if available
+func (se *SpeedEstimator) readTemperature() *float64 {
+	se.RLock()
+	temperatureG := se.temperatureG
+	se.RUnlock()
+
+	if temperatureG == nil {
+		return nil
+	}
+
+	t, err := temperatureG()
+	if err != nil {
+		se.log.DEBUG.Printf("speed estimator: temperature: %v", err)
+		return nil
+	}
+
+	return &t
+}
+
+// temperatureOffset returns the SoC offset by which a cold battery starts tapering earlier
+func (se *SpeedEstimator) temperatureOffset() float64 {
+	if se.temperature == nil {
+		return 0
+	}
+
+	offset := (se.config.TemperatureReference - *se.temperature) * se.config.TemperatureCoefficient
+	return min(max(offset, 0), maxTemperatureOffset)
+}
+
 // updateSocEstimation calculates estimated SoC based on power reduction curve
 func (se *SpeedEstimator) updateSocEstimation(currentPower float64) {
-	// Simple linear estimation based on power reduction
-	// This is a basic implementation - could be enhanced with more sophisticated curves
-	powerReduction := (se.maxPower - currentPower) / se.maxPower
+	ratio := currentPower / se.maxPower
 
-	// Assume linear relationship between power reduction and SoC increase
-	// This is vehicle-specific and could be calibrated
-	socIncrease := powerReduction * 30 // Assume 30% SoC range where power reduces significantly
+	// Cold batteries taper at lower SoC
+	offset := se.temperatureOffset()
 
-	// Estimate current SoC (assuming we started estimation around 70% and target is 80%)
-	baseSoc := float64(se.config.TargetSoc) - 10 // Start estimation 10% before target
-	se.estimatedSoc = baseSoc + socIncrease
+	if se.curve.Valid() {
+		// Use the learned charging curve of the vehicle
+		if soc, ok := se.curve.Soc(ratio); ok {
+			se.estimatedSoc = max(se.estimatedSoc, soc-offset)
+		}
+	} else {
+		// Simple linear estimation based on power reduction
+		// Assume linear relationship between power reduction and SoC increase
+		socIncrease := (1 - ratio) * 30 // Assume 30% SoC range where power reduces significantly
+
+		// Estimate current SoC (assuming we started estimation around 70% and target is 80%)
+		baseSoc := float64(se.config.TargetSoc) - 10 // Start estimation 10% before target
+		se.estimatedSoc = baseSoc + socIncrease - offset
+	}
 
 	// Clamp to reasonable range
 	se.estimatedSoc = math.Max(0, math.Min(100, se.estimatedSoc))
@@ -220,23 +701,30 @@ func (se *SpeedEstimator) updateSocEstimation(currentPower float64) {
 		se.log.INFO.Printf("speed estimator: target SoC %d%% reached (estimated: %.1f%%)", se.config.TargetSoc, se.estimatedSoc)
 	}
 
-	se.log.DEBUG.Printf("speed estimator: power %.0fW (%.1f%% of max), estimated SoC: %.1f%%",
-		currentPower, (currentPower/se.maxPower)*100, se.estimatedSoc)
+	se.log.DEBUG.Printf("speed estimator: power %.0fW (%.1f%% of max), estimated SoC: %.1f%% (temperature offset: %.1f%%)",
+		currentPower, ratio*100, se.estimatedSoc, offset)
 }
 
-// cleanOldMeasurements removes measurements older than retention period
+// cleanOldMeasurements removes measurements older than retention period.
+// The vehicle's taper is kept for learning the charging curve at the end of the session.
 func (se *SpeedEstimator) cleanOldMeasurements() {
 	cutoff := se.clock.Now().Add(-se.config.HistoryRetention)
 
 	var filtered []PowerMeasurement
 	for _, m := range se.powerHistory {
-		if m.Timestamp.After(cutoff) {
+		if m.Timestamp.After(cutoff) || se.taper(m) {
 			filtered = append(filtered, m)
 		}
 	}
 	se.powerHistory = filtered
 }
 
+// taper returns true if the measurement is part of the vehicle's power taper (no mutex)
+func (se *SpeedEstimator) taper(m PowerMeasurement) bool {
+	return !m.Timestamp.Before(se.referenceStart) && !m.limited(se.config.ReductionThreshold) &&
+		m.Power > 0 && m.Power < se.maxPower*curveMaxRatio
+}
+
 // IsTargetReached returns true if the target SoC has been reached
 func (se *SpeedEstimator) IsTargetReached() bool {
 	se.RLock()
@@ -251,6 +739,35 @@ func (se *SpeedEstimator) GetEstimatedSoc() float64 {
 	return se.estimatedSoc
 }
 
+// TaperSoc returns the estimated SoC if it is based on the learned charging curve
+func (se *SpeedEstimator) TaperSoc() (float64, bool) {
+	se.RLock()
+	defer se.RUnlock()
+	return se.estimatedSoc, se.estimationActive && se.curve.Valid() && se.estimatedSoc > 0
+}
+
+// MaxPower returns the max power observed in the current session
+func (se *SpeedEstimator) MaxPower() float64 {
+	se.RLock()
+	defer se.RUnlock()
+	return se.maxPower
+}
+
+// TargetSoc returns the configured target SoC
+func (se *SpeedEstimator) TargetSoc() int {
+	se.RLock()
+	defer se.RUnlock()
+	return se.config.TargetSoc
+}
+
+// VehicleFinished returns true if the vehicle stopped charging on its own and whether the battery is considered full.
+// A vehicle that is not full has reached its own charge limit.
+func (se *SpeedEstimator) VehicleFinished() (finished, full bool) {
+	se.RLock()
+	defer se.RUnlock()
+	return se.vehicleFinished, se.vehicleFull
+}
+
 // IsEstimationActive returns true if SoC estimation is currently active
 func (se *SpeedEstimator) IsEstimationActive() bool {
 	se.RLock()
@@ -264,13 +781,20 @@ func (se *SpeedEstimator) GetStatus() map[string]interface{} {
 	defer se.RUnlock()
 
 	return map[string]interface{}{
-		"enabled":          se.config.Enabled,
-		"estimationActive": se.estimationActive,
-		"estimatedSoc":     se.estimatedSoc,
-		"targetSoc":        se.config.TargetSoc,
-		"targetReached":    se.targetReached,
-		"maxPower":         se.maxPower,
-		"measurementCount": len(se.powerHistory),
-		"chargingDuration": se.clock.Now().Sub(se.chargingStarted).String(),
+		"enabled":               se.config.Enabled,
+		"estimationActive":      se.estimationActive,
+		"estimatedSoc":          se.estimatedSoc,
+		"targetSoc":             se.config.TargetSoc,
+		"targetReached":         se.targetReached,
+		"vehicleFinished":       se.vehicleFinished,
+		"vehicleFull":           se.vehicleFull,
+		"maxPower":              se.maxPower,
+		"measurementCount":      len(se.powerHistory),
+		"curveSessions":         se.curve.Sessions,
+		"curve":                 se.curve.Points,
+		"temperature":           se.temperature,
+		"reductionThreshold":    se.config.ReductionThreshold,
+		"minPowerForEstimation": se.config.MinPowerForEstimation,
+		"chargingDuration":      se.clock.Now().Sub(se.chargingStarted).String(),
 	}
 }
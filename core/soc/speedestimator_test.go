@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/settings"
 	"github.com/evcc-io/evcc/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -72,7 +74,7 @@ func TestSpeedEstimator_UpdatePowerDisabled(t *testing.T) {
 	estimator.StartCharging()
 
 	// Update power should do nothing when disabled
-	estimator.UpdatePower(5000)
+	estimator.UpdatePower(5000, 0)
 
 	assert.Empty(t, estimator.powerHistory)
 	assert.Equal(t, 0.0, estimator.maxPower)
@@ -91,18 +93,18 @@ func TestSpeedEstimator_UpdatePowerSampling(t *testing.T) {
 	estimator.StartCharging()
 
 	// First update should be recorded
-	estimator.UpdatePower(5000)
+	estimator.UpdatePower(5000, 0)
 	assert.Len(t, estimator.powerHistory, 1)
 	assert.Equal(t, 5000.0, estimator.maxPower)
 
 	// Second update within sample interval should be ignored
 	mockClock.Add(10 * time.Second)
-	estimator.UpdatePower(4500)
+	estimator.UpdatePower(4500, 0)
 	assert.Len(t, estimator.powerHistory, 1) // Still only 1 measurement
 
 	// Third update after sample interval should be recorded
 	mockClock.Add(25 * time.Second) // Total 35 seconds
-	estimator.UpdatePower(4500)
+	estimator.UpdatePower(4500, 0)
 	assert.Len(t, estimator.powerHistory, 2)
 }
 
@@ -119,20 +121,20 @@ func TestSpeedEstimator_MaxPowerTracking(t *testing.T) {
 	estimator.StartCharging()
 
 	// Update with increasing power
-	estimator.UpdatePower(3000)
+	estimator.UpdatePower(3000, 0)
 	assert.Equal(t, 3000.0, estimator.maxPower)
 
 	mockClock.Add(2 * time.Second)
-	estimator.UpdatePower(5000)
+	estimator.UpdatePower(5000, 0)
 	assert.Equal(t, 5000.0, estimator.maxPower)
 
 	mockClock.Add(2 * time.Second)
-	estimator.UpdatePower(4000) // Lower power shouldn't update max
+	estimator.UpdatePower(4000, 0) // Lower power shouldn't update max
 	assert.Equal(t, 5000.0, estimator.maxPower)
 
 	// After max power window, higher power should still update max
 	mockClock.Add(12 * time.Minute)
-	estimator.UpdatePower(6000)
+	estimator.UpdatePower(6000, 0)
 	assert.Equal(t, 5000.0, estimator.maxPower) // Shouldn't update after window
 }
 
@@ -152,24 +154,24 @@ func TestSpeedEstimator_EstimationActivation(t *testing.T) {
 	estimator.StartCharging()
 
 	// Build up max power
-	estimator.UpdatePower(5000)
+	estimator.UpdatePower(5000, 0)
 	mockClock.Add(2 * time.Second)
-	estimator.UpdatePower(5200)
+	estimator.UpdatePower(5200, 0)
 
 	// Not enough time passed
 	mockClock.Add(5 * time.Minute)
-	estimator.UpdatePower(4000) // 23% reduction
+	estimator.UpdatePower(4000, 0) // 23% reduction
 	assert.False(t, estimator.IsEstimationActive())
 
 	// Enough time passed but need stable reduction
 	mockClock.Add(12 * time.Minute) // Total 17 minutes
-	estimator.UpdatePower(4000)
+	estimator.UpdatePower(4000, 0)
 	assert.False(t, estimator.IsEstimationActive()) // Need stability window
 
 	// Add more measurements for stability
 	for i := 0; i < 6; i++ {
 		mockClock.Add(1 * time.Minute)
-		estimator.UpdatePower(4000 - float64(i*10)) // Gradually decreasing
+		estimator.UpdatePower(4000-float64(i*10), 0) // Gradually decreasing
 	}
 
 	assert.True(t, estimator.IsEstimationActive())
@@ -192,7 +194,7 @@ func TestSpeedEstimator_SocEstimation(t *testing.T) {
 	estimator.StartCharging()
 
 	// Build up max power
-	estimator.UpdatePower(5000)
+	estimator.UpdatePower(5000, 0)
 
 	// Wait for minimum charging time
 	mockClock.Add(2 * time.Minute)
@@ -200,7 +202,7 @@ func TestSpeedEstimator_SocEstimation(t *testing.T) {
 	// Add stable reduced power measurements over the stability window
 	reducedPower := 4000.0 // 20% reduction from 5000W
 	for i := 0; i < 5; i++ {
-		estimator.UpdatePower(reducedPower)
+		estimator.UpdatePower(reducedPower, 0)
 		mockClock.Add(20 * time.Second) // Spread over stability window
 	}
 
@@ -208,7 +210,7 @@ func TestSpeedEstimator_SocEstimation(t *testing.T) {
 
 	// Continue with further power reduction
 	mockClock.Add(1 * time.Second)
-	estimator.UpdatePower(3500) // 30% reduction
+	estimator.UpdatePower(3500, 0) // 30% reduction
 
 	estimatedSoc := estimator.GetEstimatedSoc()
 	assert.Greater(t, estimatedSoc, 70.0) // Should be above base
@@ -216,7 +218,7 @@ func TestSpeedEstimator_SocEstimation(t *testing.T) {
 
 	// Reduce power enough to trigger target
 	mockClock.Add(1 * time.Second)
-	estimator.UpdatePower(3000) // 40% reduction
+	estimator.UpdatePower(3000, 0) // 40% reduction
 
 	// Should reach target
 	assert.True(t, estimator.IsTargetReached())
@@ -237,7 +239,7 @@ func TestSpeedEstimator_HistoryCleanup(t *testing.T) {
 
 	// Add measurements over time
 	for i := 0; i < 20; i++ {
-		estimator.UpdatePower(5000)
+		estimator.UpdatePower(5000, 0)
 		mockClock.Add(1 * time.Minute)
 	}
 
@@ -253,7 +255,7 @@ func TestSpeedEstimator_GetStatus(t *testing.T) {
 
 	estimator := NewSpeedEstimator(log, config)
 	estimator.StartCharging()
-	estimator.UpdatePower(5000)
+	estimator.UpdatePower(5000, 0)
 
 	status := estimator.GetStatus()
 
@@ -266,3 +268,432 @@ func TestSpeedEstimator_GetStatus(t *testing.T) {
 	assert.Equal(t, 1, status["measurementCount"])
 	assert.NotEmpty(t, status["chargingDuration"])
 }
+
+func TestSpeedEstimator_LearnedCurve(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MinChargingTime = 1 * time.Minute
+	config.StabilityWindow = 15 * time.Minute
+	config.TargetSoc = 80
+	config.Capacity = 10
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	// full power, then taper down until vehicle stops
+	estimator.UpdatePower(10000, 0)
+	for _, power := range []float64{10000, 10000, 8000, 7000, 6000, 5000, 4000, 3000, 2000} {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(power, 0)
+	}
+	assert.True(t, estimator.IsEstimationActive())
+
+	// vehicle stopped while charger enabled
+	estimator.FinishCharging(0)
+	estimator.StopCharging()
+
+	curve := estimator.Curve()
+	assert.True(t, curve.Valid())
+	assert.Equal(t, 1, curve.Sessions)
+
+	// next session uses learned curve instead of linear model
+	estimator.Reset()
+	estimator.StartCharging()
+	estimator.UpdatePower(10000, 0)
+	for _, power := range []float64{10000, 10000, 6000, 6000, 6000} {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(power, 0)
+	}
+
+	assert.True(t, estimator.IsEstimationActive())
+	soc, _ := curve.Soc(0.6)
+	assert.Equal(t, soc, estimator.GetEstimatedSoc())
+	assert.Equal(t, soc >= 80, estimator.IsTargetReached())
+}
+
+func TestSpeedEstimator_EndSession(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MinChargingTime = 1 * time.Minute
+	config.StabilityWindow = 10 * time.Minute
+	config.HistoryRetention = 15 * time.Minute
+	config.Capacity = 10
+
+	store := settings.NewDatabaseSettingsAdapter("test.speedestimator.endsession.")
+
+	estimator := NewSpeedEstimator(log, config)
+	estimator.SetStore(store)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	// taper longer than the history retention
+	estimator.UpdatePower(10000, 0)
+	for _, power := range []float64{10000, 8000, 7000, 6000, 5000, 4000} {
+		for range 5 {
+			mockClock.Add(time.Minute)
+			estimator.UpdatePower(power, 0)
+		}
+	}
+	assert.True(t, estimator.IsEstimationActive())
+
+	// charging stopped by the loadpoint, nothing learned yet
+	estimator.StopCharging()
+	assert.False(t, estimator.Curve().Valid())
+
+	// vehicle disconnected, whole taper is learned once
+	estimator.EndSession()
+	estimator.EndSession()
+
+	curve := estimator.Curve()
+	assert.Equal(t, 1, curve.Sessions)
+	assert.Len(t, curve.Points, 5)
+
+	// reset curve is stored as valid empty curve
+	estimator.ResetCurve()
+
+	var stored ChargingCurve
+	assert.NoError(t, store.Json(keys.SpeedEstimatorCurve, &stored))
+	assert.False(t, stored.Valid())
+}
+
+func TestSpeedEstimator_FinishChargingWithoutTaper(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.Capacity = 10
+
+	estimator := NewSpeedEstimator(log, config)
+	estimator.StartCharging()
+	estimator.UpdatePower(10000, 0)
+
+	// no taper observed, nothing to learn
+	estimator.FinishCharging(0)
+	assert.False(t, estimator.Curve().Valid())
+
+	// vehicle reached its own limit
+	finished, full := estimator.VehicleFinished()
+	assert.True(t, finished)
+	assert.False(t, full)
+	assert.False(t, estimator.IsTargetReached())
+}
+
+func TestSpeedEstimator_FinishChargingAfterTaper(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.Capacity = 10
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	// taper before min charging time and stability window
+	for _, power := range []float64{10000, 7000, 4000} {
+		estimator.UpdatePower(power, 0)
+		mockClock.Add(2 * time.Minute)
+	}
+	assert.False(t, estimator.IsEstimationActive())
+
+	// power collapse while charger enabled
+	estimator.FinishCharging(100)
+
+	finished, full := estimator.VehicleFinished()
+	assert.True(t, finished)
+	assert.True(t, full)
+	assert.Equal(t, 100.0, estimator.GetEstimatedSoc())
+	assert.True(t, estimator.IsTargetReached())
+	assert.Equal(t, 1, estimator.Curve().Sessions)
+
+	// finished state survives restart
+	state := estimator.State()
+	assert.True(t, state.VehicleFull)
+
+	estimator.Reset()
+	finished, _ = estimator.VehicleFinished()
+	assert.False(t, finished)
+}
+
+func TestSpeedEstimator_Trickle(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	estimator.UpdatePower(11000, 11000)
+
+	// vehicle limited by the loadpoint is no trickle
+	for range 4 {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(800, 900)
+	}
+	finished, _ := estimator.VehicleFinished()
+	assert.False(t, finished)
+
+	// vehicle trickling although more power is offered
+	for range 3 {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(500, 11000)
+	}
+	finished, _ = estimator.VehicleFinished()
+	assert.False(t, finished, "trickle duration not elapsed")
+
+	mockClock.Add(5 * time.Minute)
+	estimator.UpdatePower(500, 11000)
+
+	finished, full := estimator.VehicleFinished()
+	assert.True(t, finished)
+	assert.True(t, full)
+	assert.True(t, estimator.IsTargetReached())
+}
+
+func TestSpeedEstimator_PauseResume(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+	started := mockClock.Now()
+
+	estimator.UpdatePower(6000, 0)
+	mockClock.Add(time.Hour)
+	estimator.UpdatePower(6000, 0)
+	assert.Equal(t, 6000.0, estimator.powerHistory[1].Energy)
+
+	// pause keeps history
+	estimator.StopCharging()
+	mockClock.Add(30 * time.Minute)
+	estimator.StartCharging()
+
+	assert.Equal(t, started, estimator.chargingStarted)
+	assert.Len(t, estimator.powerHistory, 2)
+	assert.Equal(t, 6000.0, estimator.maxPower)
+
+	// no energy integrated during pause
+	estimator.UpdatePower(6000, 0)
+	assert.Equal(t, 6000.0, estimator.powerHistory[2].Energy)
+
+	estimator.Reset()
+	assert.Empty(t, estimator.powerHistory)
+	assert.True(t, estimator.chargingStarted.IsZero())
+}
+
+func TestSpeedEstimator_StateRestore(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.HistoryRetention = time.Hour
+
+	mockClock := clock.NewMock()
+
+	estimator := NewSpeedEstimator(log, config)
+	estimator.clock = mockClock
+	estimator.StartCharging()
+	estimator.UpdatePower(5000, 0)
+
+	state := estimator.State()
+	assert.Len(t, state.PowerHistory, 1)
+
+	restored := NewSpeedEstimator(log, config)
+	restored.clock = mockClock
+	assert.True(t, restored.Restore(state))
+	assert.Equal(t, 5000.0, restored.maxPower)
+	assert.Equal(t, state.ChargingStarted, restored.chargingStarted)
+
+	// outdated state
+	mockClock.Add(2 * time.Hour)
+	restored = NewSpeedEstimator(log, config)
+	restored.clock = mockClock
+	assert.False(t, restored.Restore(state))
+	assert.Empty(t, restored.powerHistory)
+}
+
+func TestSpeedEstimator_OfferedPowerLimit(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MinChargingTime = 1 * time.Minute
+	config.MaxPowerWindow = 5 * time.Minute
+	config.StabilityWindow = 3 * time.Minute
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	// full power
+	estimator.UpdatePower(11000, 11040)
+
+	// pv mode reduces offered current, vehicle follows
+	for range 10 {
+		mockClock.Add(1 * time.Minute)
+		estimator.UpdatePower(4100, 4140)
+	}
+	assert.False(t, estimator.IsEstimationActive(), "loadpoint limit is not taper")
+
+	// vehicle draws less than offered
+	for range 5 {
+		mockClock.Add(1 * time.Minute)
+		estimator.UpdatePower(7000, 11040)
+	}
+	assert.True(t, estimator.IsEstimationActive(), "vehicle limit is taper")
+}
+
+func TestSpeedEstimator_LimitedMaxPower(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MaxPowerWindow = 5 * time.Minute
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	// max power window only sees pv limited power
+	estimator.UpdatePower(4100, 4140)
+	assert.True(t, estimator.maxPowerLimited)
+
+	// limited max power may increase after window
+	mockClock.Add(10 * time.Minute)
+	estimator.UpdatePower(11000, 11040)
+	assert.Equal(t, 11000.0, estimator.maxPower)
+}
+
+func TestSpeedEstimator_RuntimeSettings(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+
+	store := settings.NewDatabaseSettingsAdapter("test.speedestimator.")
+
+	estimator := NewSpeedEstimator(log, config)
+	estimator.SetStore(store)
+
+	assert.Error(t, estimator.SetTargetSoc(0))
+	assert.Error(t, estimator.SetReductionThreshold(1))
+	assert.Error(t, estimator.SetMinPowerForEstimation(-1))
+
+	assert.NoError(t, estimator.SetTargetSoc(70))
+	assert.NoError(t, estimator.SetReductionThreshold(0.2))
+	assert.NoError(t, estimator.SetMinPowerForEstimation(2000))
+
+	// settings are restored for the same vehicle
+	estimator = NewSpeedEstimator(log, config)
+	estimator.SetStore(store)
+
+	assert.Equal(t, 70, estimator.Config().TargetSoc)
+	assert.Equal(t, 0.2, estimator.Config().ReductionThreshold)
+	assert.Equal(t, 2000.0, estimator.Config().MinPowerForEstimation)
+}
+
+func TestSpeedEstimator_ManualCalibration(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MinChargingTime = 1 * time.Minute
+	config.StabilityWindow = 15 * time.Minute
+	config.Capacity = 10
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	estimator.UpdatePower(10000, 0)
+	for _, power := range []float64{10000, 10000, 8000, 7000, 6000} {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(power, 0)
+	}
+	assert.True(t, estimator.IsEstimationActive())
+
+	// user confirms actual soc below target
+	assert.True(t, estimator.Calibrate(60))
+	assert.Equal(t, 60.0, estimator.GetEstimatedSoc())
+	assert.False(t, estimator.IsTargetReached())
+	assert.Equal(t, 1, estimator.Curve().Sessions)
+
+	// forget learned curve
+	estimator.ResetCurve()
+	assert.False(t, estimator.Curve().Valid())
+}
+
+func TestSpeedEstimator_ResetReference(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MinChargingTime = 10 * time.Minute
+	config.StabilityWindow = 15 * time.Minute
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+	estimator.StartCharging()
+
+	// 3p charging
+	estimator.UpdatePower(11000, 0)
+	for range 3 {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(11000, 0)
+	}
+
+	// switched to 1p, power drop is not a taper
+	estimator.ResetReference("phase switch")
+	for range 3 {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(3600, 0)
+	}
+
+	assert.False(t, estimator.IsEstimationActive())
+	assert.Equal(t, 3600.0, estimator.State().MaxPower)
+}
+
+func TestSpeedEstimator_Temperature(t *testing.T) {
+	log := util.NewLogger("test")
+	config := DefaultChargingSpeedConfig()
+	config.Enabled = true
+	config.SampleInterval = 1 * time.Second
+	config.MinChargingTime = 1 * time.Minute
+	config.StabilityWindow = 15 * time.Minute
+
+	estimator := NewSpeedEstimator(log, config)
+	mockClock := clock.NewMock()
+	estimator.clock = mockClock
+
+	// winter session, taper expected 20% earlier
+	estimator.SetTemperature(func() (float64, error) {
+		return 0, nil
+	})
+	estimator.StartCharging()
+
+	estimator.UpdatePower(10000, 0)
+	for _, power := range []float64{10000, 10000, 6000, 6000, 6000} {
+		mockClock.Add(5 * time.Minute)
+		estimator.UpdatePower(power, 0)
+	}
+
+	assert.True(t, estimator.IsEstimationActive())
+	assert.InDelta(t, 62, estimator.GetEstimatedSoc(), 1e-6)
+	assert.False(t, estimator.IsTargetReached())
+}
@@ -144,3 +144,34 @@ func (v *adapter) GetRepeatingPlans() []api.RepeatingPlanStruct {
 
 	return []api.RepeatingPlanStruct{}
 }
+
+// GetDepartureSoc returns the soc of repeating plans created from learned departures, 0 if disabled
+func (v *adapter) GetDepartureSoc() int {
+	if v, err := settings.Int(v.key() + keys.DepartureSoc); err == nil {
+		return int(v)
+	}
+	return 0
+}
+
+// SetDepartureSoc sets the soc of repeating plans created from learned departures
+func (v *adapter) SetDepartureSoc(soc int) {
+	v.log.DEBUG.Printf("set %s departure soc: %d", v.name, soc)
+	settings.SetInt(v.key()+keys.DepartureSoc, int64(soc))
+	v.publish()
+}
+
+// GetDeparturePlans returns the repeating plans created from learned departures
+func (v *adapter) GetDeparturePlans() []api.RepeatingPlanStruct {
+	var plans []api.RepeatingPlanStruct
+	if err := settings.Json(v.key()+keys.DeparturePlans, &plans); err == nil {
+		return plans
+	}
+	return nil
+}
+
+// SetDeparturePlans stores the repeating plans created from learned departures
+func (v *adapter) SetDeparturePlans(plans []api.RepeatingPlanStruct) {
+	if err := settings.SetJson(v.key()+keys.DeparturePlans, plans); err != nil {
+		v.log.ERROR.Printf("set %s departure plans: %v", v.name, err)
+	}
+}
@@ -48,6 +48,15 @@ type API interface {
 	// SetRepeatingPlans stores every repeating plan
 	SetRepeatingPlans([]api.RepeatingPlanStruct) error
 
+	// GetDepartureSoc returns the soc of repeating plans created from learned departures, 0 if disabled
+	GetDepartureSoc() int
+	// SetDepartureSoc sets the soc of repeating plans created from learned departures
+	SetDepartureSoc(soc int)
+	// GetDeparturePlans returns the repeating plans created from learned departures
+	GetDeparturePlans() []api.RepeatingPlanStruct
+	// SetDeparturePlans stores the repeating plans created from learned departures
+	SetDeparturePlans([]api.RepeatingPlanStruct)
+
 	// // GetMinCurrent returns the min charging current
 	// GetMinCurrent() float64
 	// // SetMinCurrent sets the min charging current
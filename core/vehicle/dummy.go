@@ -56,3 +56,21 @@ func (v *dummy) SetRepeatingPlans(plans []api.RepeatingPlanStruct) error {
 func (v *dummy) GetRepeatingPlans() []api.RepeatingPlanStruct {
 	return []api.RepeatingPlanStruct{}
 }
+
+// GetDepartureSoc returns the departure soc
+func (v *dummy) GetDepartureSoc() int {
+	return 0
+}
+
+// SetDepartureSoc sets the departure soc
+func (v *dummy) SetDepartureSoc(soc int) {
+}
+
+// GetDeparturePlans returns the repeating plans created from learned departures
+func (v *dummy) GetDeparturePlans() []api.RepeatingPlanStruct {
+	return nil
+}
+
+// SetDeparturePlans stores the repeating plans created from learned departures
+func (v *dummy) SetDeparturePlans(plans []api.RepeatingPlanStruct) {
+}
@@ -41,6 +41,34 @@ func (m *MockAPI) EXPECT() *MockAPIMockRecorder {
 	return m.recorder
 }
 
+// GetDeparturePlans mocks base method.
+func (m *MockAPI) GetDeparturePlans() []api.RepeatingPlanStruct {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeparturePlans")
+	ret0, _ := ret[0].([]api.RepeatingPlanStruct)
+	return ret0
+}
+
+// GetDeparturePlans indicates an expected call of GetDeparturePlans.
+func (mr *MockAPIMockRecorder) GetDeparturePlans() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeparturePlans", reflect.TypeOf((*MockAPI)(nil).GetDeparturePlans))
+}
+
+// GetDepartureSoc mocks base method.
+func (m *MockAPI) GetDepartureSoc() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepartureSoc")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetDepartureSoc indicates an expected call of GetDepartureSoc.
+func (mr *MockAPIMockRecorder) GetDepartureSoc() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepartureSoc", reflect.TypeOf((*MockAPI)(nil).GetDepartureSoc))
+}
+
 // GetLimitSoc mocks base method.
 func (m *MockAPI) GetLimitSoc() int {
 	m.ctrl.T.Helper()
@@ -127,6 +155,30 @@ func (mr *MockAPIMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockAPI)(nil).Name))
 }
 
+// SetDeparturePlans mocks base method.
+func (m *MockAPI) SetDeparturePlans(arg0 []api.RepeatingPlanStruct) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDeparturePlans", arg0)
+}
+
+// SetDeparturePlans indicates an expected call of SetDeparturePlans.
+func (mr *MockAPIMockRecorder) SetDeparturePlans(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeparturePlans", reflect.TypeOf((*MockAPI)(nil).SetDeparturePlans), arg0)
+}
+
+// SetDepartureSoc mocks base method.
+func (m *MockAPI) SetDepartureSoc(soc int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDepartureSoc", soc)
+}
+
+// SetDepartureSoc indicates an expected call of SetDepartureSoc.
+func (mr *MockAPIMockRecorder) SetDepartureSoc(soc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDepartureSoc", reflect.TypeOf((*MockAPI)(nil).SetDepartureSoc), soc)
+}
+
 // SetLimitSoc mocks base method.
 func (m *MockAPI) SetLimitSoc(soc int) {
 	m.ctrl.T.Helper()
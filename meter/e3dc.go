@@ -27,7 +27,7 @@ func init() {
 	registry.Add("e3dc-rscp", NewE3dcFromConfig)
 }
 
-//go:generate go tool decorate -f decorateE3dc -b *E3dc -r api.Meter -t "api.Battery,Soc,func() (float64, error)" -t "api.BatteryCapacity,Capacity,func() float64" -t "api.BatteryController,SetBatteryMode,func(api.BatteryMode) error" -t "api.MaxACPowerGetter,MaxACPower,func() float64"
+//go:generate go tool decorate -f decorateE3dc -b *E3dc -r api.Meter -t "api.Battery,Soc,func() (float64, error)" -t "api.BatteryCapacity,Capacity,func() float64" -t "api.BatteryController,SetBatteryMode,func(api.BatteryMode) error" -t "api.BatteryPowerController,SetBatteryPower,func(float64) error" -t "api.MaxACPowerGetter,MaxACPower,func() float64"
 
 func NewE3dcFromConfig(other map[string]interface{}) (api.Meter, error) {
 	cc := struct {
@@ -94,15 +94,17 @@ func NewE3dc(cfg rscp.ClientConfig, usage templates.Usage, dischargeLimit uint32
 		batteryCapacity func() float64
 		batterySoc      func() (float64, error)
 		batteryMode     func(api.BatteryMode) error
+		batteryPower    func(float64) error
 	)
 
 	if usage == templates.UsageBattery {
 		batteryCapacity = capacity
 		batterySoc = m.batterySoc
 		batteryMode = m.setBatteryMode
+		batteryPower = m.setBatteryPower
 	}
 
-	return decorateE3dc(m, batterySoc, batteryCapacity, batteryMode, maxacpower), nil
+	return decorateE3dc(m, batterySoc, batteryCapacity, batteryMode, batteryPower, maxacpower), nil
 }
 
 func (m *E3dc) CurrentPower() (float64, error) {
@@ -206,6 +208,41 @@ func (m *E3dc) setBatteryMode(mode api.BatteryMode) error {
 	return err
 }
 
+// setBatteryPower limits the discharge power or charges from grid with the given power
+func (m *E3dc) setBatteryPower(power float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var msg []rscp.Message
+
+	if power < 0 {
+		msg = []rscp.Message{
+			e3dcChargeBatteryLimit(uint32(-power)),
+			e3dcBatteryCharge(50000), // max. 50kWh
+		}
+	} else {
+		msg = []rscp.Message{
+			e3dcDischargeBatteryLimit(true, uint32(power)),
+			e3dcBatteryCharge(0),
+		}
+	}
+
+	res, err := m.conn.SendMultiple(msg)
+	if err != nil {
+		m.conn.Disconnect()
+	} else {
+		err = rscpError(res...)
+	}
+	return err
+}
+
+func e3dcChargeBatteryLimit(limit uint32) rscp.Message {
+	return *rscp.NewMessage(rscp.EMS_REQ_SET_POWER_SETTINGS, []rscp.Message{
+		*rscp.NewMessage(rscp.EMS_POWER_LIMITS_USED, true),
+		*rscp.NewMessage(rscp.EMS_MAX_CHARGE_POWER, limit),
+	})
+}
+
 func e3dcDischargeBatteryLimit(active bool, limit uint32) rscp.Message {
 	contents := []rscp.Message{
 		*rscp.NewMessage(rscp.EMS_POWER_LIMITS_USED, active),
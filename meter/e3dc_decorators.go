@@ -6,12 +6,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity func() float64, batteryController func(api.BatteryMode) error, maxACPowerGetter func() float64) api.Meter {
+func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity func() float64, batteryController func(api.BatteryMode) error, batteryPowerController func(float64) error, maxACPowerGetter func() float64) api.Meter {
 	switch {
 	case battery == nil && maxACPowerGetter == nil:
 		return base
 
-	case battery != nil && batteryCapacity == nil && batteryController == nil && maxACPowerGetter == nil:
+	case battery != nil && batteryCapacity == nil && batteryController == nil && batteryPowerController == nil && maxACPowerGetter == nil:
 		return &struct {
 			*E3dc
 			api.Battery
@@ -22,7 +22,7 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			},
 		}
 
-	case battery != nil && batteryCapacity != nil && batteryController == nil && maxACPowerGetter == nil:
+	case battery != nil && batteryCapacity != nil && batteryController == nil && batteryPowerController == nil && maxACPowerGetter == nil:
 		return &struct {
 			*E3dc
 			api.Battery
@@ -37,7 +37,7 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			},
 		}
 
-	case battery != nil && batteryCapacity == nil && batteryController != nil && maxACPowerGetter == nil:
+	case battery != nil && batteryCapacity == nil && batteryController != nil && batteryPowerController == nil && maxACPowerGetter == nil:
 		return &struct {
 			*E3dc
 			api.Battery
@@ -52,7 +52,7 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			},
 		}
 
-	case battery != nil && batteryCapacity != nil && batteryController != nil && maxACPowerGetter == nil:
+	case battery != nil && batteryCapacity != nil && batteryController != nil && batteryPowerController == nil && maxACPowerGetter == nil:
 		return &struct {
 			*E3dc
 			api.Battery
@@ -71,6 +71,82 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			},
 		}
 
+	case battery != nil && batteryCapacity == nil && batteryController == nil && batteryPowerController != nil && maxACPowerGetter == nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryPowerController
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
+		}
+
+	case battery != nil && batteryCapacity != nil && batteryController == nil && batteryPowerController != nil && maxACPowerGetter == nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryCapacity
+			api.BatteryPowerController
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryCapacity: &decorateE3dcBatteryCapacityImpl{
+				batteryCapacity: batteryCapacity,
+			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
+		}
+
+	case battery != nil && batteryCapacity == nil && batteryController != nil && batteryPowerController != nil && maxACPowerGetter == nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryController
+			api.BatteryPowerController
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateE3dcBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
+		}
+
+	case battery != nil && batteryCapacity != nil && batteryController != nil && batteryPowerController != nil && maxACPowerGetter == nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryCapacity
+			api.BatteryController
+			api.BatteryPowerController
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryCapacity: &decorateE3dcBatteryCapacityImpl{
+				batteryCapacity: batteryCapacity,
+			},
+			BatteryController: &decorateE3dcBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
+		}
+
 	case battery == nil && maxACPowerGetter != nil:
 		return &struct {
 			*E3dc
@@ -82,26 +158,107 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			},
 		}
 
-	case battery != nil && batteryCapacity == nil && batteryController == nil && maxACPowerGetter != nil:
+	case battery != nil && batteryCapacity == nil && batteryController == nil && batteryPowerController == nil && maxACPowerGetter != nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.MaxACPowerGetter
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
+				maxACPowerGetter: maxACPowerGetter,
+			},
+		}
+
+	case battery != nil && batteryCapacity != nil && batteryController == nil && batteryPowerController == nil && maxACPowerGetter != nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryCapacity
+			api.MaxACPowerGetter
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryCapacity: &decorateE3dcBatteryCapacityImpl{
+				batteryCapacity: batteryCapacity,
+			},
+			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
+				maxACPowerGetter: maxACPowerGetter,
+			},
+		}
+
+	case battery != nil && batteryCapacity == nil && batteryController != nil && batteryPowerController == nil && maxACPowerGetter != nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryController
+			api.MaxACPowerGetter
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateE3dcBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
+				maxACPowerGetter: maxACPowerGetter,
+			},
+		}
+
+	case battery != nil && batteryCapacity != nil && batteryController != nil && batteryPowerController == nil && maxACPowerGetter != nil:
+		return &struct {
+			*E3dc
+			api.Battery
+			api.BatteryCapacity
+			api.BatteryController
+			api.MaxACPowerGetter
+		}{
+			E3dc: base,
+			Battery: &decorateE3dcBatteryImpl{
+				battery: battery,
+			},
+			BatteryCapacity: &decorateE3dcBatteryCapacityImpl{
+				batteryCapacity: batteryCapacity,
+			},
+			BatteryController: &decorateE3dcBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
+				maxACPowerGetter: maxACPowerGetter,
+			},
+		}
+
+	case battery != nil && batteryCapacity == nil && batteryController == nil && batteryPowerController != nil && maxACPowerGetter != nil:
 		return &struct {
 			*E3dc
 			api.Battery
+			api.BatteryPowerController
 			api.MaxACPowerGetter
 		}{
 			E3dc: base,
 			Battery: &decorateE3dcBatteryImpl{
 				battery: battery,
 			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
 			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
 				maxACPowerGetter: maxACPowerGetter,
 			},
 		}
 
-	case battery != nil && batteryCapacity != nil && batteryController == nil && maxACPowerGetter != nil:
+	case battery != nil && batteryCapacity != nil && batteryController == nil && batteryPowerController != nil && maxACPowerGetter != nil:
 		return &struct {
 			*E3dc
 			api.Battery
 			api.BatteryCapacity
+			api.BatteryPowerController
 			api.MaxACPowerGetter
 		}{
 			E3dc: base,
@@ -111,16 +268,20 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			BatteryCapacity: &decorateE3dcBatteryCapacityImpl{
 				batteryCapacity: batteryCapacity,
 			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
 			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
 				maxACPowerGetter: maxACPowerGetter,
 			},
 		}
 
-	case battery != nil && batteryCapacity == nil && batteryController != nil && maxACPowerGetter != nil:
+	case battery != nil && batteryCapacity == nil && batteryController != nil && batteryPowerController != nil && maxACPowerGetter != nil:
 		return &struct {
 			*E3dc
 			api.Battery
 			api.BatteryController
+			api.BatteryPowerController
 			api.MaxACPowerGetter
 		}{
 			E3dc: base,
@@ -130,17 +291,21 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			BatteryController: &decorateE3dcBatteryControllerImpl{
 				batteryController: batteryController,
 			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
 			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
 				maxACPowerGetter: maxACPowerGetter,
 			},
 		}
 
-	case battery != nil && batteryCapacity != nil && batteryController != nil && maxACPowerGetter != nil:
+	case battery != nil && batteryCapacity != nil && batteryController != nil && batteryPowerController != nil && maxACPowerGetter != nil:
 		return &struct {
 			*E3dc
 			api.Battery
 			api.BatteryCapacity
 			api.BatteryController
+			api.BatteryPowerController
 			api.MaxACPowerGetter
 		}{
 			E3dc: base,
@@ -153,6 +318,9 @@ func decorateE3dc(base *E3dc, battery func() (float64, error), batteryCapacity f
 			BatteryController: &decorateE3dcBatteryControllerImpl{
 				batteryController: batteryController,
 			},
+			BatteryPowerController: &decorateE3dcBatteryPowerControllerImpl{
+				batteryPowerController: batteryPowerController,
+			},
 			MaxACPowerGetter: &decorateE3dcMaxACPowerGetterImpl{
 				maxACPowerGetter: maxACPowerGetter,
 			},
@@ -186,6 +354,14 @@ func (impl *decorateE3dcBatteryControllerImpl) SetBatteryMode(p0 api.BatteryMode
 	return impl.batteryController(p0)
 }
 
+type decorateE3dcBatteryPowerControllerImpl struct {
+	batteryPowerController func(float64) error
+}
+
+func (impl *decorateE3dcBatteryPowerControllerImpl) SetBatteryPower(p0 float64) error {
+	return impl.batteryPowerController(p0)
+}
+
 type decorateE3dcMaxACPowerGetterImpl struct {
 	maxACPowerGetter func() float64
 }
@@ -14,7 +14,7 @@ func init() {
 	registry.AddCtx(api.Custom, NewConfigurableFromConfig)
 }
 
-//go:generate go tool decorate -f decorateMeter -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.PhaseCurrents,Currents,func() (float64, float64, float64, error)" -t "api.PhaseVoltages,Voltages,func() (float64, float64, float64, error)" -t "api.PhasePowers,Powers,func() (float64, float64, float64, error)" -t "api.Battery,Soc,func() (float64, error)" -t "api.BatteryCapacity,Capacity,func() float64" -t "api.MaxACPowerGetter,MaxACPower,func() float64" -t "api.BatteryController,SetBatteryMode,func(api.BatteryMode) error"
+//go:generate go tool decorate -f decorateMeter -b *Meter -r api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.PhaseCurrents,Currents,func() (float64, float64, float64, error)" -t "api.PhaseVoltages,Voltages,func() (float64, float64, float64, error)" -t "api.PhasePowers,Powers,func() (float64, float64, float64, error)" -t "api.Battery,Soc,func() (float64, error)" -t "api.BatteryCapacity,Capacity,func() float64" -t "api.MaxACPowerGetter,MaxACPower,func() float64" -t "api.BatteryController,SetBatteryMode,func(api.BatteryMode) error"
 
 // NewConfigurableFromConfig creates api.Meter from config
 func NewConfigurableFromConfig(ctx context.Context, other map[string]interface{}) (api.Meter, error) {
@@ -29,6 +29,10 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]interface{}
 		Soc               *plugin.Config // optional
 		LimitSoc          *plugin.Config // optional
 		BatteryMode       *plugin.Config // optional
+		BatteryPower      *plugin.Config // optional
+
+		// pv
+		PowerLimit *plugin.Config // optional
 	}{
 		batterySocLimits: batterySocLimits{
 			MinSoc: 20,
@@ -80,6 +84,24 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]interface{}
 		}
 	}
 
+	batPowerS, err := cc.BatteryPower.FloatSetter(ctx, "batteryPower")
+	if err != nil {
+		return nil, fmt.Errorf("battery power: %w", err)
+	}
+
+	powerLimitS, err := cc.PowerLimit.FloatSetter(ctx, "powerLimit")
+	if err != nil {
+		return nil, fmt.Errorf("power limit: %w", err)
+	}
+
+	if batPowerS != nil {
+		m.capabilities = append(m.capabilities, batteryPowerController(batPowerS))
+	}
+
+	if powerLimitS != nil {
+		m.capabilities = append(m.capabilities, powerLimiter(powerLimitS))
+	}
+
 	res := m.Decorate(energyG, currentsG, voltagesG, powersG, socG, cc.batteryCapacity.Decorator(), cc.batteryMaxACPower.Decorator(), batModeS)
 
 	return res, nil
@@ -96,6 +118,7 @@ func NewConfigurable(currentPowerG func() (float64, error)) (*Meter, error) {
 // Meter is an api.Meter implementation with configurable getters and setters.
 type Meter struct {
 	currentPowerG func() (float64, error)
+	capabilities  []any
 }
 
 // Decorate attaches additional capabilities to the base meter
@@ -110,6 +133,25 @@ func (m *Meter) Decorate(
 	return decorateMeter(m, totalEnergy, currents, voltages, powers, batterySoc, batteryCapacity, maxACPower, setBatteryMode)
 }
 
+// Capabilities implements the api.CapabilityProvider interface
+func (m *Meter) Capabilities() []any {
+	return m.capabilities
+}
+
+// batteryPowerController implements the api.BatteryPowerController interface
+type batteryPowerController func(float64) error
+
+func (f batteryPowerController) SetBatteryPower(power float64) error {
+	return f(power)
+}
+
+// powerLimiter implements the api.PowerLimiter interface
+type powerLimiter func(float64) error
+
+func (f powerLimiter) SetPowerLimit(limit float64) error {
+	return f(limit)
+}
+
 // CurrentPower implements the api.Meter interface
 func (m *Meter) CurrentPower() (float64, error) {
 	return m.currentPowerG()
@@ -12,8 +12,9 @@ import (
 
 // Event is a notification event
 type Event struct {
-	Loadpoint *int // optional loadpoint id
-	Event     string
+	Loadpoint  *int // optional loadpoint id
+	Event      string
+	Attributes map[string]any // optional event attributes, e.g. of the affected circuit
 }
 
 // EventTemplateConfig is the push message configuration for an event
@@ -76,6 +77,11 @@ func (h *Hub) apply(ev Event, tmpl string) (string, error) {
 		}
 	}
 
+	// event attributes
+	for k, v := range ev.Attributes {
+		attr[k] = v
+	}
+
 	// add missing attributes
 	if name, ok := attr["vehicleName"].(string); ok {
 		if v, err := h.vehicles.ByName(name); err == nil {
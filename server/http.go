@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	eapi "github.com/evcc-io/evcc/api"
@@ -141,9 +142,12 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 		"bufferstartsoc":          {"POST", "/bufferstartsoc/{value:[0-9.]+}", floatHandler(site.SetBufferStartSoc, site.GetBufferStartSoc)},
 		"batterydischargecontrol": {"POST", "/batterydischargecontrol/{value:[01truefalse]+}", boolHandler(site.SetBatteryDischargeControl, site.GetBatteryDischargeControl)},
 		"batterygridcharge":       {"POST", "/batterygridchargelimit/{value:-?[0-9.]+}", floatPtrHandler(pass(site.SetBatteryGridChargeLimit), site.GetBatteryGridChargeLimit)},
+		"batterygridchargepower":  {"POST", "/batterygridchargepower/{value:[0-9.]+}", floatHandler(site.SetBatteryGridChargePower, site.GetBatteryGridChargePower)},
 		"batterygridchargedelete": {"DELETE", "/batterygridchargelimit", floatPtrHandler(pass(site.SetBatteryGridChargeLimit), site.GetBatteryGridChargeLimit)},
 		"batterymode":             {"POST", "/batterymode/{value:[a-z]+}", updateBatteryMode(site)},
 		"batterymodedelete":       {"DELETE", "/batterymode", updateBatteryMode(site)},
+		"batteryplan":             {"GET", "/battery/plan", batteryPlanHandler(site)},
+		"peakshaving":             {"GET", "/peakshaving", getHandler(site.GetPeakShaving)},
 		"prioritysoc":             {"POST", "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoc, site.GetPrioritySoc)},
 		"residualpower":           {"POST", "/residualpower/{value:-?[0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
 		"smartcost":               {"POST", "/smartcostlimit/{value:-?[0-9.]+}", updateSmartCostLimit(site, smartCostLimit)},
@@ -151,9 +155,13 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 		"smartfeedin":             {"POST", "/smartfeedinprioritylimit/{value:-?[0-9.]+}", updateSmartCostLimit(site, smartFeedInPriorityLimit)},
 		"smartfeedindelete":       {"DELETE", "/smartfeedinprioritylimit", updateSmartCostLimit(site, smartFeedInPriorityLimit)},
 		"tariff":                  {"GET", "/tariff/{tariff:[a-z]+}", tariffHandler(site)},
+		"forecasthome":            {"GET", "/forecast/home", homeForecastHandler(site)},
+		"schedules":               {"GET", "/schedules", getHandler(site.GetSchedules)},
+		"schedules2":              {"POST", "/schedules", updateSchedulesHandler(site)},
 		"sessions":                {"GET", "/sessions", sessionHandler},
 		"updatesession":           {"PUT", "/session/{id:[0-9]+}", updateSessionHandler},
 		"deletesession":           {"DELETE", "/session/{id:[0-9]+}", deleteSessionHandler},
+		"sessioncurve":            {"GET", "/sessions/{id:[0-9]+}/curve", sessionCurveHandler},
 		"telemetry":               {"GET", "/settings/telemetry", getHandler(telemetry.Enabled)},
 		"telemetry2":              {"POST", "/settings/telemetry/{value:[01truefalse]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
 	}
@@ -169,6 +177,8 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 		"plan":           {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/soc/{value:[0-9]+}/{time:[0-9TZ:.+-]+}", planSocHandler(site)},
 		"plan2":          {"DELETE", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/soc", planSocRemoveHandler(site)},
 		"repeatingPlans": {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/repeating", addRepeatingPlansHandler(site)},
+		"departures":     {"GET", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/departures", departuresHandler(site)},
+		"departureSoc":   {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/departures/soc/{value:[0-9]+}", departureSocHandler(site)},
 
 		// config ui
 		// "mode":       {"POST", "/mode/{value:[a-z]+}", chargeModeHandler(v)},
@@ -212,6 +222,12 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, valueChan chan<- util.Param)
 			"smartFeedInPriorityDelete": {"DELETE", "/smartfeedinprioritylimit", floatPtrHandler(pass(lp.SetSmartFeedInPriorityLimit), lp.GetSmartFeedInPriorityLimit)},
 			"priority":                  {"POST", "/priority/{value:[0-9]+}", intHandler(pass(lp.SetPriority), lp.GetPriority)},
 			"batteryBoost":              {"POST", "/batteryboost/{value:[01truefalse]+}", boolHandler(lp.SetBatteryBoost, func() bool { return lp.GetBatteryBoost() > 0 })},
+			"speedEstimator":            {"GET", "/speedestimator", speedEstimatorStatusHandler(lp)},
+			"speedEstimatorReset":       {"DELETE", "/speedestimator", speedEstimatorResetHandler(lp)},
+			"speedEstimatorTargetSoc":   {"POST", "/speedestimator/targetsoc/{value:[0-9]+}", speedEstimatorHandler(lp, strconv.Atoi, lp.SetSpeedEstimatorTargetSoc)},
+			"speedEstimatorThreshold":   {"POST", "/speedestimator/threshold/{value:[0-9.]+}", speedEstimatorHandler(lp, parseFloat, lp.SetSpeedEstimatorReductionThreshold)},
+			"speedEstimatorMinPower":    {"POST", "/speedestimator/minpower/{value:[0-9.]+}", speedEstimatorHandler(lp, parseFloat, lp.SetSpeedEstimatorMinPower)},
+			"speedEstimatorSoc":         {"POST", "/speedestimator/soc/{value:[0-9.]+}", speedEstimatorHandler(lp, parseFloat, lp.CalibrateSpeedEstimator)},
 		}
 
 		for _, r := range routes {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/util"
 	"github.com/gorilla/mux"
@@ -41,6 +42,50 @@ func remoteDemandHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// speedEstimatorStatusHandler returns the speed estimator status of the active vehicle
+func speedEstimatorStatusHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := lp.GetSpeedEstimatorStatus()
+		if res == nil {
+			jsonError(w, http.StatusNotFound, errors.New("speed estimator not available"))
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// speedEstimatorHandler updates the speed estimator of the active vehicle and returns its status
+func speedEstimatorHandler[T any](lp loadpoint.API, conv func(string) (T, error), set func(T) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		val, err := conv(vars["value"])
+		if err == nil {
+			err = set(val)
+		}
+
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, lp.GetSpeedEstimatorStatus())
+	}
+}
+
+// speedEstimatorResetHandler resets the speed estimator of the active vehicle
+func speedEstimatorResetHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := lp.ResetSpeedEstimator(); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, lp.GetSpeedEstimatorStatus())
+	}
+}
+
 // planHandler returns the current plan
 func planHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -51,21 +96,23 @@ func planHandler(lp loadpoint.API) http.HandlerFunc {
 		goal, _ := lp.GetPlanGoal()
 		precondition := lp.GetPlanPreCondDuration()
 		requiredDuration := lp.GetPlanRequiredDuration(goal, maxPower)
-		plan := lp.GetPlan(planTime, requiredDuration, precondition)
+		profile := lp.GetPlanProfile(planTime, requiredDuration, precondition)
 
 		res := struct {
-			PlanId       int       `json:"planId"`
-			PlanTime     time.Time `json:"planTime"`
-			Duration     int64     `json:"duration"`
-			Precondition int64     `json:"precondition"`
-			Plan         api.Rates `json:"plan"`
-			Power        float64   `json:"power"`
+			PlanId       int               `json:"planId"`
+			PlanTime     time.Time         `json:"planTime"`
+			Duration     int64             `json:"duration"`
+			Precondition int64             `json:"precondition"`
+			Plan         api.Rates         `json:"plan"`
+			Profile      planner.PowerPlan `json:"profile"`
+			Power        float64           `json:"power"`
 		}{
 			PlanId:       id,
 			PlanTime:     planTime,
 			Duration:     int64(requiredDuration.Seconds()),
 			Precondition: int64(precondition.Seconds()),
-			Plan:         plan,
+			Plan:         profile.Rates(),
+			Profile:      profile,
 			Power:        maxPower,
 		}
 
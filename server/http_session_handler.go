@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/evcc-io/evcc/api"
@@ -28,6 +29,20 @@ func csvResult(ctx context.Context, w http.ResponseWriter, res any, filename str
 	}
 }
 
+// csvContext returns a context with the requested or accepted language for csv export
+func csvContext(r *http.Request) context.Context {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		// get request language
+		lang = r.Header.Get("Accept-Language")
+		if tags, _, err := language.ParseAcceptLanguage(lang); err == nil && len(tags) > 0 {
+			lang = tags[0].String()
+		}
+	}
+
+	return context.WithValue(context.Background(), locale.Locale, lang)
+}
+
 // sessionHandler returns the list of charging sessions
 func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	if db.Instance == nil {
@@ -73,17 +88,42 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if r.URL.Query().Get("format") == "csv" {
-		lang := r.URL.Query().Get("lang")
-		if lang == "" {
-			// get request language
-			lang = r.Header.Get("Accept-Language")
-			if tags, _, err := language.ParseAcceptLanguage(lang); err == nil && len(tags) > 0 {
-				lang = tags[0].String()
-			}
-		}
+		csvResult(csvContext(r), w, &res, filename)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// sessionCurveHandler returns the charging curve of the session with given id
+func sessionCurveHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
 
-		ctx := context.WithValue(context.Background(), locale.Locale, lang)
-		csvResult(ctx, w, &res, filename)
+	id := mux.Vars(r)["id"]
+
+	sid, err := strconv.ParseUint(id, 10, 0)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	store, err := session.NewStore("", db.Instance)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res, err := store.Curve(uint(sid))
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		csvResult(csvContext(r), w, &res, "session-"+id+"-curve")
 		return
 	}
 
@@ -107,6 +147,11 @@ func deleteSessionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if txn := db.Instance.Where("session_id = ?", id).Delete(new(session.CurvePoint)); txn.Error != nil {
+		jsonError(w, http.StatusBadRequest, txn.Error)
+		return
+	}
+
 	jsonResult(w, res)
 }
 
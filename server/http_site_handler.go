@@ -13,6 +13,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/schedule"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/server/assets"
 	"github.com/evcc-io/evcc/util"
@@ -305,6 +306,83 @@ func tariffHandler(site site.API) http.HandlerFunc {
 	}
 }
 
+// homeForecastHandler returns the expected household consumption
+func homeForecastHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rates, err := site.GetHomeForecast()
+		if err != nil {
+			jsonError(w, http.StatusNotFound, err)
+			return
+		}
+
+		res := struct {
+			Rates api.Rates `json:"rates"`
+		}{
+			Rates: rates,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// batteryPlanHandler returns the battery optimizer's plan
+func batteryPlanHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plan, err := site.GetBatteryPlan()
+		if err != nil {
+			jsonError(w, http.StatusNotFound, err)
+			return
+		}
+
+		type slot struct {
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end"`
+			Price float64   `json:"price"`
+			Mode  string    `json:"mode"`
+			Power float64   `json:"power"`
+			Soc   float64   `json:"soc"`
+			Grid  float64   `json:"grid"`
+		}
+
+		res := make([]slot, 0, len(plan))
+		for _, s := range plan {
+			res = append(res, slot{
+				Start: s.Start,
+				End:   s.End,
+				Price: s.Value,
+				Mode:  s.Mode.String(),
+				Power: s.Power,
+				Soc:   s.Soc,
+				Grid:  s.Grid,
+			})
+		}
+
+		jsonResult(w, struct {
+			Plan []slot `json:"plan"`
+		}{
+			Plan: res,
+		})
+	}
+}
+
+// updateSchedulesHandler replaces the scheduler configuration
+func updateSchedulesHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var res schedule.Config
+		if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := site.SetSchedules(res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, site.GetSchedules())
+	}
+}
+
 // socketHandler attaches websocket handler to uri
 func socketHandler(hub *SocketHub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/gorilla/mux"
 )
@@ -170,3 +171,77 @@ func planSocRemoveHandler(site site.API) http.HandlerFunc {
 		jsonResult(w, res)
 	}
 }
+
+// departuresHandler returns the learned departures and the repeating plans suggested for them
+func departuresHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		v, err := site.Vehicles().ByName(vars["name"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		departures, err := site.GetDepartures(vars["name"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		soc := v.GetDepartureSoc()
+		if soc == 0 {
+			soc = v.GetLimitSoc()
+		}
+
+		tz, _ := session.DepartureTimezone()
+
+		res := struct {
+			Soc        int                       `json:"soc"`
+			Departures []session.Departure       `json:"departures"`
+			Plans      []api.RepeatingPlanStruct `json:"plans"`
+		}{
+			Soc:        soc,
+			Departures: departures,
+			Plans:      session.RepeatingPlans(departures, session.DepartureConfidence, soc, tz),
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// departureSocHandler sets the departure soc and creates repeating plans for the learned departures
+func departureSocHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		v, err := site.Vehicles().ByName(vars["name"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		soc, err := strconv.Atoi(vars["value"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		v.SetDepartureSoc(soc)
+
+		if err := site.ApplyDepartures(vars["name"]); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct {
+			Soc   int                       `json:"soc"`
+			Plans []api.RepeatingPlanStruct `json:"plans"`
+		}{
+			Soc:   v.GetDepartureSoc(),
+			Plans: v.GetRepeatingPlans(),
+		}
+
+		jsonResult(w, res)
+	}
+}
@@ -248,6 +248,16 @@ func (m *MQTT) listenLoadpointSetters(topic string, site site.API, lp loadpoint.
 		{"smartCostLimit", floatPtrSetter(pass(lp.SetSmartCostLimit))},
 		{"smartFeedInPriorityLimit", floatPtrSetter(pass(lp.SetSmartFeedInPriorityLimit))},
 		{"batteryBoost", boolSetter(lp.SetBatteryBoost)},
+		{"speedEstimatorTargetSoc", intSetter(lp.SetSpeedEstimatorTargetSoc)},
+		{"speedEstimatorReductionThreshold", floatSetter(lp.SetSpeedEstimatorReductionThreshold)},
+		{"speedEstimatorMinPower", floatSetter(lp.SetSpeedEstimatorMinPower)},
+		{"speedEstimatorSoc", floatSetter(lp.CalibrateSpeedEstimator)},
+		{"speedEstimatorReset", boolSetter(func(reset bool) error {
+			if !reset {
+				return nil
+			}
+			return lp.ResetSpeedEstimator()
+		})},
 		{"planEnergy", func(payload string) error {
 			var plan struct {
 				Time         time.Time `json:"time"`
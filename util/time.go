@@ -2,9 +2,40 @@ package util
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
+// Timezone returns the IANA name of the local timezone
+func Timezone() string {
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" && !filepath.IsAbs(tz) {
+		if _, err := time.LoadLocation(tz); err == nil {
+			return tz
+		}
+	}
+
+	if tz := time.Local.String(); tz != "Local" {
+		return tz
+	}
+
+	// system timezone
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if _, tz, ok := strings.Cut(target, "zoneinfo/"); ok {
+			return tz
+		}
+	}
+
+	if b, err := os.ReadFile("/etc/timezone"); err == nil {
+		if tz := strings.TrimSpace(string(b)); tz != "" {
+			return tz
+		}
+	}
+
+	return time.Local.String()
+}
+
 // GetNextOccurrence returns the next occurrence of the given time on the specified weekdays.
 func GetNextOccurrence(weekdays []int, timeStr string, tz string) (time.Time, error) {
 	loc, err := time.LoadLocation(tz)
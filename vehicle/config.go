@@ -45,9 +45,22 @@ func NewFromConfig(ctx context.Context, typ string, other map[string]interface{}
 	}
 
 	v, err := factory(ctx, cc.Other)
+	if err == nil {
+		err = initChargingSpeedLimit(ctx, v)
+	}
 	if err != nil {
 		err = fmt.Errorf("cannot create vehicle type '%s': %w", typ, err)
 	}
 
 	return v, err
 }
+
+// initChargingSpeedLimit creates the charging speed limit plugins of vehicles embedding the common configuration
+func initChargingSpeedLimit(ctx context.Context, v api.Vehicle) error {
+	if vv, ok := v.(interface {
+		initChargingSpeedLimit(context.Context) error
+	}); ok {
+		return vv.initChargingSpeedLimit(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package vehicle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargingSpeedLimitDecorated(t *testing.T) {
+	v, err := NewFromConfig(context.TODO(), api.Custom, map[string]any{
+		"soc":    map[string]any{"source": "const", "value": 50},
+		"status": map[string]any{"source": "const", "value": "B"},
+		"chargingSpeedLimit": map[string]any{
+			"enabled":              true,
+			"temperatureReference": 0,
+			"temperature":          map[string]any{"source": "const", "value": -5},
+		},
+	})
+	require.NoError(t, err)
+
+	// decorated vehicle still provides the configuration
+	_, ok := v.(api.ChargeState)
+	require.True(t, ok)
+
+	vv, ok := v.(interface {
+		GetChargingSpeedLimitConfig() map[string]any
+	})
+	require.True(t, ok)
+
+	config := vv.GetChargingSpeedLimitConfig()
+	assert.Equal(t, true, config["enabled"])
+	assert.Equal(t, 0.0, config["temperatureReference"])
+
+	temperatureG, ok := config["temperature"].(func() (float64, error))
+	require.True(t, ok)
+	temp, err := temperatureG()
+	require.NoError(t, err)
+	assert.Equal(t, -5.0, temp)
+}
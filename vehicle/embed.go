@@ -1,7 +1,11 @@
 package vehicle
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/plugin"
 )
 
 // TODO align phases with OnIdentify
@@ -25,7 +29,14 @@ type embed struct {
 		HistoryRetention      string  `mapstructure:"historyRetention"`      // How long to keep power history (default: "2h")
 		StabilityWindow       string  `mapstructure:"stabilityWindow"`       // Window to check for stable power reduction (default: "5m")
 		MinPowerForEstimation float64 `mapstructure:"minPowerForEstimation"` // Minimum power to consider for estimation (default: 1000W)
+		TrickleDuration       string  `mapstructure:"trickleDuration"`       // Charging below min power for this duration marks the vehicle full (default: "15m")
+
+		Temperature            *plugin.Config `mapstructure:"temperature"`            // Optional ambient temperature (°C)
+		TemperatureReference   *float64       `mapstructure:"temperatureReference"`   // Temperature above which the taper is not affected (default: 20°C)
+		TemperatureCoefficient float64        `mapstructure:"temperatureCoefficient"` // SoC % the taper moves down per °C below reference (default: 1.0)
 	} `mapstructure:"chargingSpeedLimit"`
+
+	temperatureG func() (float64, error) // ambient temperature getter of the charging speed limit
 }
 
 // Title implements the api.Vehicle interface
@@ -96,5 +107,30 @@ func (v *embed) GetChargingSpeedLimitConfig() map[string]interface{} {
 	config["historyRetention"] = v.ChargingSpeedLimit.HistoryRetention
 	config["stabilityWindow"] = v.ChargingSpeedLimit.StabilityWindow
 	config["minPowerForEstimation"] = v.ChargingSpeedLimit.MinPowerForEstimation
+	config["trickleDuration"] = v.ChargingSpeedLimit.TrickleDuration
+	if v.ChargingSpeedLimit.TemperatureReference != nil {
+		config["temperatureReference"] = *v.ChargingSpeedLimit.TemperatureReference
+	}
+	config["temperatureCoefficient"] = v.ChargingSpeedLimit.TemperatureCoefficient
+	if v.temperatureG != nil {
+		config["temperature"] = v.temperatureG
+	}
 	return config
 }
+
+// initChargingSpeedLimit creates the ambient temperature getter of the charging speed limit
+func (v *embed) initChargingSpeedLimit(ctx context.Context) error {
+	cc := v.ChargingSpeedLimit.Temperature
+	if cc == nil || v.temperatureG != nil {
+		return nil
+	}
+
+	temperatureG, err := cc.FloatGetter(ctx)
+	if err != nil {
+		return fmt.Errorf("charging speed limit temperature: %w", err)
+	}
+
+	v.temperatureG = temperatureG
+
+	return nil
+}
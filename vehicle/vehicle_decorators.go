@@ -8,14 +8,14 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeState func() (api.ChargeStatus, error), vehicleRange func() (int64, error), vehicleOdometer func() (float64, error), vehicleClimater func() (bool, error), currentController func(int64) error, currentGetter func() (float64, error), vehicleFinishTimer func() (time.Time, error), resurrector func() error, chargeController func(bool) error) api.Vehicle {
+func decorateVehicle(base *Vehicle, socLimiter func() (int64, error), chargeState func() (api.ChargeStatus, error), vehicleRange func() (int64, error), vehicleOdometer func() (float64, error), vehicleClimater func() (bool, error), currentController func(int64) error, currentGetter func() (float64, error), vehicleFinishTimer func() (time.Time, error), resurrector func() error, chargeController func(bool) error) api.Vehicle {
 	switch {
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return base
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 		}{
 			Vehicle: base,
@@ -26,7 +26,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 		}{
 			Vehicle: base,
@@ -37,7 +37,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 		}{
@@ -52,7 +52,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleRange
 		}{
 			Vehicle: base,
@@ -63,7 +63,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleRange
 		}{
@@ -78,7 +78,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleRange
 		}{
@@ -93,7 +93,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleRange
@@ -112,7 +112,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleOdometer
 		}{
 			Vehicle: base,
@@ -123,7 +123,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleOdometer
 		}{
@@ -138,7 +138,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleOdometer
 		}{
@@ -153,7 +153,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleOdometer
@@ -172,7 +172,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleOdometer
 			api.VehicleRange
 		}{
@@ -187,7 +187,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleOdometer
 			api.VehicleRange
@@ -206,7 +206,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleOdometer
 			api.VehicleRange
@@ -225,7 +225,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleOdometer
@@ -248,7 +248,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 		}{
 			Vehicle: base,
@@ -259,7 +259,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 		}{
@@ -274,7 +274,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 		}{
@@ -289,7 +289,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -308,7 +308,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleRange
 		}{
@@ -323,7 +323,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleRange
@@ -342,7 +342,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleRange
@@ -361,7 +361,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -384,7 +384,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleOdometer
 		}{
@@ -399,7 +399,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -418,7 +418,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -437,7 +437,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -460,7 +460,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleOdometer
 			api.VehicleRange
@@ -479,7 +479,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -502,7 +502,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -525,7 +525,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -552,7 +552,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 		}{
 			Vehicle: base,
@@ -563,7 +563,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 		}{
@@ -578,7 +578,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 		}{
@@ -593,7 +593,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -612,7 +612,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleRange
 		}{
@@ -627,7 +627,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleRange
@@ -646,7 +646,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleRange
@@ -665,7 +665,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -688,7 +688,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleOdometer
 		}{
@@ -703,7 +703,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleOdometer
@@ -722,7 +722,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleOdometer
@@ -741,7 +741,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -764,7 +764,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleOdometer
 			api.VehicleRange
@@ -783,7 +783,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleOdometer
@@ -806,7 +806,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleOdometer
@@ -829,7 +829,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -856,7 +856,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 		}{
@@ -871,7 +871,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -890,7 +890,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -909,7 +909,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -932,7 +932,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleRange
@@ -951,7 +951,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -974,7 +974,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -997,7 +997,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -1024,7 +1024,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -1043,7 +1043,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -1066,7 +1066,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -1089,7 +1089,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -1116,7 +1116,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -1139,7 +1139,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -1166,7 +1166,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -1193,7 +1193,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -1224,7 +1224,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 		}{
 			Vehicle: base,
@@ -1235,7 +1235,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 		}{
@@ -1250,7 +1250,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 		}{
@@ -1265,7 +1265,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1284,7 +1284,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleRange
 		}{
@@ -1299,7 +1299,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleRange
@@ -1318,7 +1318,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleRange
@@ -1337,7 +1337,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1360,7 +1360,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleOdometer
 		}{
@@ -1375,7 +1375,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleOdometer
@@ -1394,7 +1394,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleOdometer
@@ -1413,7 +1413,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1436,7 +1436,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleOdometer
 			api.VehicleRange
@@ -1455,7 +1455,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleOdometer
@@ -1478,7 +1478,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleOdometer
@@ -1501,7 +1501,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1528,7 +1528,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 		}{
@@ -1543,7 +1543,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -1562,7 +1562,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -1581,7 +1581,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1604,7 +1604,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleRange
@@ -1623,7 +1623,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -1646,7 +1646,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -1669,7 +1669,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1696,7 +1696,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -1715,7 +1715,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -1738,7 +1738,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -1761,7 +1761,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1788,7 +1788,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -1811,7 +1811,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -1838,7 +1838,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -1865,7 +1865,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -1896,7 +1896,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 		}{
@@ -1911,7 +1911,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -1930,7 +1930,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -1949,7 +1949,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -1972,7 +1972,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleRange
@@ -1991,7 +1991,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2014,7 +2014,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2037,7 +2037,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2064,7 +2064,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleOdometer
@@ -2083,7 +2083,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2106,7 +2106,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2129,7 +2129,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2156,7 +2156,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleOdometer
@@ -2179,7 +2179,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2206,7 +2206,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2233,7 +2233,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2264,7 +2264,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -2283,7 +2283,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2306,7 +2306,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2329,7 +2329,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2356,7 +2356,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -2379,7 +2379,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2406,7 +2406,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2433,7 +2433,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2464,7 +2464,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -2487,7 +2487,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2514,7 +2514,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2541,7 +2541,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2572,7 +2572,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -2599,7 +2599,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -2630,7 +2630,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2661,7 +2661,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -2696,7 +2696,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleFinishTimer
 		}{
 			Vehicle: base,
@@ -2707,7 +2707,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleFinishTimer
 		}{
@@ -2722,7 +2722,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleFinishTimer
 		}{
@@ -2737,7 +2737,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -2756,7 +2756,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleFinishTimer
 			api.VehicleRange
 		}{
@@ -2771,7 +2771,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -2790,7 +2790,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -2809,7 +2809,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -2832,7 +2832,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleFinishTimer
 			api.VehicleOdometer
 		}{
@@ -2847,7 +2847,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -2866,7 +2866,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -2885,7 +2885,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -2908,7 +2908,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleFinishTimer
 			api.VehicleOdometer
 			api.VehicleRange
@@ -2927,7 +2927,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -2950,7 +2950,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -2973,7 +2973,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -3000,7 +3000,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleFinishTimer
 		}{
@@ -3015,7 +3015,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3034,7 +3034,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3053,7 +3053,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -3076,7 +3076,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -3095,7 +3095,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3118,7 +3118,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3141,7 +3141,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -3168,7 +3168,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -3187,7 +3187,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3210,7 +3210,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3233,7 +3233,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -3260,7 +3260,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.VehicleClimater
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -3283,7 +3283,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.SocLimiter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3310,7 +3310,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3337,7 +3337,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.SocLimiter
 			api.VehicleClimater
@@ -3368,7 +3368,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleFinishTimer
 		}{
@@ -3383,7 +3383,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -3402,7 +3402,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -3421,7 +3421,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -3444,7 +3444,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -3463,7 +3463,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -3486,7 +3486,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -3509,7 +3509,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -3536,7 +3536,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -3555,7 +3555,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -3578,7 +3578,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -3601,7 +3601,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -3628,7 +3628,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -3651,7 +3651,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -3678,7 +3678,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -3705,7 +3705,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -3736,7 +3736,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3755,7 +3755,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -3778,7 +3778,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -3801,7 +3801,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -3828,7 +3828,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3851,7 +3851,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -3878,7 +3878,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -3905,7 +3905,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -3936,7 +3936,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -3959,7 +3959,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -3986,7 +3986,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -4013,7 +4013,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -4044,7 +4044,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -4071,7 +4071,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.SocLimiter
 			api.VehicleClimater
@@ -4102,7 +4102,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.VehicleClimater
@@ -4133,7 +4133,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.SocLimiter
@@ -4168,7 +4168,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleFinishTimer
 		}{
@@ -4183,7 +4183,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -4202,7 +4202,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -4221,7 +4221,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4244,7 +4244,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -4263,7 +4263,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -4286,7 +4286,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -4309,7 +4309,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4336,7 +4336,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -4355,7 +4355,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -4378,7 +4378,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -4401,7 +4401,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4428,7 +4428,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -4451,7 +4451,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -4478,7 +4478,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -4505,7 +4505,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4536,7 +4536,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -4555,7 +4555,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -4578,7 +4578,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -4601,7 +4601,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4628,7 +4628,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -4651,7 +4651,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -4678,7 +4678,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -4705,7 +4705,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4736,7 +4736,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -4759,7 +4759,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -4786,7 +4786,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -4813,7 +4813,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4844,7 +4844,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -4871,7 +4871,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.SocLimiter
 			api.VehicleClimater
@@ -4902,7 +4902,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.VehicleClimater
@@ -4933,7 +4933,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.SocLimiter
@@ -4968,7 +4968,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -4987,7 +4987,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5010,7 +5010,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5033,7 +5033,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5060,7 +5060,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -5083,7 +5083,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5110,7 +5110,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5137,7 +5137,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5168,7 +5168,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -5191,7 +5191,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5218,7 +5218,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5245,7 +5245,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5276,7 +5276,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -5303,7 +5303,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5334,7 +5334,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5365,7 +5365,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5400,7 +5400,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -5423,7 +5423,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5450,7 +5450,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5477,7 +5477,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5508,7 +5508,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -5535,7 +5535,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5566,7 +5566,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5597,7 +5597,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5632,7 +5632,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -5659,7 +5659,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5690,7 +5690,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5721,7 +5721,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5756,7 +5756,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -5787,7 +5787,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.SocLimiter
@@ -5822,7 +5822,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5857,7 +5857,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -5896,7 +5896,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 		}{
 			Vehicle: base,
@@ -5907,7 +5907,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 		}{
@@ -5922,7 +5922,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 		}{
@@ -5937,7 +5937,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -5956,7 +5956,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleRange
 		}{
@@ -5971,7 +5971,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleRange
@@ -5990,7 +5990,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleRange
@@ -6009,7 +6009,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6032,7 +6032,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleOdometer
 		}{
@@ -6047,7 +6047,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleOdometer
@@ -6066,7 +6066,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleOdometer
@@ -6085,7 +6085,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6108,7 +6108,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleOdometer
 			api.VehicleRange
@@ -6127,7 +6127,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleOdometer
@@ -6150,7 +6150,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleOdometer
@@ -6173,7 +6173,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6200,7 +6200,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 		}{
@@ -6215,7 +6215,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -6234,7 +6234,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -6253,7 +6253,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6276,7 +6276,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleRange
@@ -6295,7 +6295,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -6318,7 +6318,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -6341,7 +6341,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6368,7 +6368,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -6387,7 +6387,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -6410,7 +6410,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -6433,7 +6433,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6460,7 +6460,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -6483,7 +6483,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -6510,7 +6510,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -6537,7 +6537,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -6568,7 +6568,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 		}{
@@ -6583,7 +6583,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -6602,7 +6602,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6621,7 +6621,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6644,7 +6644,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleRange
@@ -6663,7 +6663,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -6686,7 +6686,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6709,7 +6709,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6736,7 +6736,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleOdometer
@@ -6755,7 +6755,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -6778,7 +6778,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6801,7 +6801,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6828,7 +6828,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleOdometer
@@ -6851,7 +6851,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -6878,7 +6878,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6905,7 +6905,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -6936,7 +6936,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -6955,7 +6955,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -6978,7 +6978,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7001,7 +7001,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7028,7 +7028,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -7051,7 +7051,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -7078,7 +7078,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7105,7 +7105,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7136,7 +7136,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -7159,7 +7159,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -7186,7 +7186,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7213,7 +7213,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7244,7 +7244,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -7271,7 +7271,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -7302,7 +7302,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7333,7 +7333,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -7368,7 +7368,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 		}{
@@ -7383,7 +7383,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7402,7 +7402,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7421,7 +7421,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7444,7 +7444,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleRange
@@ -7463,7 +7463,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7486,7 +7486,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7509,7 +7509,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7536,7 +7536,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleOdometer
@@ -7555,7 +7555,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7578,7 +7578,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7601,7 +7601,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7628,7 +7628,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleOdometer
@@ -7651,7 +7651,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7678,7 +7678,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7705,7 +7705,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7736,7 +7736,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -7755,7 +7755,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7778,7 +7778,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7801,7 +7801,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7828,7 +7828,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -7851,7 +7851,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7878,7 +7878,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7905,7 +7905,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -7936,7 +7936,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -7959,7 +7959,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -7986,7 +7986,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -8013,7 +8013,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -8044,7 +8044,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -8071,7 +8071,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -8102,7 +8102,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -8133,7 +8133,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -8168,7 +8168,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8187,7 +8187,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8210,7 +8210,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8233,7 +8233,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8260,7 +8260,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8283,7 +8283,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8310,7 +8310,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8337,7 +8337,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8368,7 +8368,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8391,7 +8391,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8418,7 +8418,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8445,7 +8445,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8476,7 +8476,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8503,7 +8503,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8534,7 +8534,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8565,7 +8565,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8600,7 +8600,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8623,7 +8623,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8650,7 +8650,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8677,7 +8677,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8708,7 +8708,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8735,7 +8735,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8766,7 +8766,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8797,7 +8797,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8832,7 +8832,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8859,7 +8859,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8890,7 +8890,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8921,7 +8921,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -8956,7 +8956,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -8987,7 +8987,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -9022,7 +9022,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -9057,7 +9057,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -9096,7 +9096,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleFinishTimer
 		}{
@@ -9111,7 +9111,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -9130,7 +9130,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -9149,7 +9149,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9172,7 +9172,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -9191,7 +9191,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -9214,7 +9214,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -9237,7 +9237,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9264,7 +9264,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -9283,7 +9283,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -9306,7 +9306,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -9329,7 +9329,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9356,7 +9356,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -9379,7 +9379,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -9406,7 +9406,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -9433,7 +9433,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9464,7 +9464,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -9483,7 +9483,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -9506,7 +9506,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -9529,7 +9529,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9556,7 +9556,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -9579,7 +9579,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -9606,7 +9606,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -9633,7 +9633,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9664,7 +9664,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -9687,7 +9687,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -9714,7 +9714,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -9741,7 +9741,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9772,7 +9772,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -9799,7 +9799,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.Resurrector
 			api.SocLimiter
 			api.VehicleClimater
@@ -9830,7 +9830,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.VehicleClimater
@@ -9861,7 +9861,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.Resurrector
 			api.SocLimiter
@@ -9896,7 +9896,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -9915,7 +9915,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -9938,7 +9938,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -9961,7 +9961,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -9988,7 +9988,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -10011,7 +10011,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10038,7 +10038,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10065,7 +10065,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10096,7 +10096,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -10119,7 +10119,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10146,7 +10146,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10173,7 +10173,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10204,7 +10204,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -10231,7 +10231,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10262,7 +10262,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10293,7 +10293,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10328,7 +10328,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -10351,7 +10351,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10378,7 +10378,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10405,7 +10405,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10436,7 +10436,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -10463,7 +10463,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10494,7 +10494,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10525,7 +10525,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10560,7 +10560,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -10587,7 +10587,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10618,7 +10618,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10649,7 +10649,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10684,7 +10684,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.VehicleClimater
@@ -10715,7 +10715,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.Resurrector
 			api.SocLimiter
@@ -10750,7 +10750,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10785,7 +10785,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.Resurrector
@@ -10824,7 +10824,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -10843,7 +10843,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -10866,7 +10866,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -10889,7 +10889,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -10916,7 +10916,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -10939,7 +10939,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -10966,7 +10966,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -10993,7 +10993,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11024,7 +11024,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -11047,7 +11047,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -11074,7 +11074,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11101,7 +11101,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11132,7 +11132,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -11159,7 +11159,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -11190,7 +11190,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11221,7 +11221,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11256,7 +11256,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -11279,7 +11279,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -11306,7 +11306,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11333,7 +11333,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11364,7 +11364,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -11391,7 +11391,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -11422,7 +11422,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11453,7 +11453,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11488,7 +11488,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -11515,7 +11515,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -11546,7 +11546,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11577,7 +11577,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11612,7 +11612,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.VehicleClimater
@@ -11643,7 +11643,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentGetter
 			api.Resurrector
 			api.SocLimiter
@@ -11678,7 +11678,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11713,7 +11713,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentGetter
 			api.Resurrector
@@ -11752,7 +11752,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -11775,7 +11775,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -11802,7 +11802,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -11829,7 +11829,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -11860,7 +11860,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -11887,7 +11887,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -11918,7 +11918,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -11949,7 +11949,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -11984,7 +11984,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12011,7 +12011,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12042,7 +12042,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12073,7 +12073,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12108,7 +12108,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12139,7 +12139,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12174,7 +12174,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12209,7 +12209,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12248,7 +12248,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12275,7 +12275,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12306,7 +12306,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12337,7 +12337,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12372,7 +12372,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12403,7 +12403,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12438,7 +12438,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12473,7 +12473,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12512,7 +12512,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12543,7 +12543,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12578,7 +12578,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12613,7 +12613,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12652,7 +12652,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12687,7 +12687,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.CurrentController
 			api.CurrentGetter
 			api.Resurrector
@@ -12726,7 +12726,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12765,7 +12765,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController == nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeState
 			api.CurrentController
 			api.CurrentGetter
@@ -12808,7 +12808,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 		}{
 			Vehicle: base,
@@ -12819,7 +12819,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 		}{
@@ -12834,7 +12834,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 		}{
@@ -12849,7 +12849,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -12868,7 +12868,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleRange
 		}{
@@ -12883,7 +12883,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleRange
@@ -12902,7 +12902,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleRange
@@ -12921,7 +12921,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -12944,7 +12944,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleOdometer
 		}{
@@ -12959,7 +12959,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleOdometer
@@ -12978,7 +12978,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleOdometer
@@ -12997,7 +12997,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -13020,7 +13020,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleOdometer
 			api.VehicleRange
@@ -13039,7 +13039,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleOdometer
@@ -13062,7 +13062,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleOdometer
@@ -13085,7 +13085,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -13112,7 +13112,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 		}{
@@ -13127,7 +13127,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -13146,7 +13146,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -13165,7 +13165,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -13188,7 +13188,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleRange
@@ -13207,7 +13207,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -13230,7 +13230,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -13253,7 +13253,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -13280,7 +13280,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -13299,7 +13299,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -13322,7 +13322,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -13345,7 +13345,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -13372,7 +13372,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleOdometer
@@ -13395,7 +13395,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -13422,7 +13422,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -13449,7 +13449,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -13480,7 +13480,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 		}{
@@ -13495,7 +13495,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -13514,7 +13514,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13533,7 +13533,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13556,7 +13556,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleRange
@@ -13575,7 +13575,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -13598,7 +13598,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13621,7 +13621,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13648,7 +13648,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleOdometer
@@ -13667,7 +13667,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -13690,7 +13690,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13713,7 +13713,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13740,7 +13740,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleOdometer
@@ -13763,7 +13763,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -13790,7 +13790,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13817,7 +13817,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13848,7 +13848,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -13867,7 +13867,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -13890,7 +13890,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13913,7 +13913,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -13940,7 +13940,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -13963,7 +13963,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -13990,7 +13990,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -14017,7 +14017,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -14048,7 +14048,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -14071,7 +14071,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -14098,7 +14098,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -14125,7 +14125,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -14156,7 +14156,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -14183,7 +14183,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -14214,7 +14214,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -14245,7 +14245,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -14280,7 +14280,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 		}{
@@ -14295,7 +14295,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14314,7 +14314,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14333,7 +14333,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14356,7 +14356,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleRange
@@ -14375,7 +14375,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14398,7 +14398,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14421,7 +14421,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14448,7 +14448,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleOdometer
@@ -14467,7 +14467,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14490,7 +14490,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14513,7 +14513,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14540,7 +14540,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleOdometer
@@ -14563,7 +14563,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14590,7 +14590,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14617,7 +14617,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14648,7 +14648,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -14667,7 +14667,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14690,7 +14690,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14713,7 +14713,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14740,7 +14740,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -14763,7 +14763,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14790,7 +14790,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14817,7 +14817,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14848,7 +14848,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -14871,7 +14871,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -14898,7 +14898,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14925,7 +14925,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -14956,7 +14956,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -14983,7 +14983,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -15014,7 +15014,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -15045,7 +15045,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -15080,7 +15080,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15099,7 +15099,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15122,7 +15122,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15145,7 +15145,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15172,7 +15172,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15195,7 +15195,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15222,7 +15222,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15249,7 +15249,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15280,7 +15280,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15303,7 +15303,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15330,7 +15330,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15357,7 +15357,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15388,7 +15388,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15415,7 +15415,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15446,7 +15446,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15477,7 +15477,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15512,7 +15512,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15535,7 +15535,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15562,7 +15562,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15589,7 +15589,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15620,7 +15620,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15647,7 +15647,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15678,7 +15678,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15709,7 +15709,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15744,7 +15744,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15771,7 +15771,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15802,7 +15802,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15833,7 +15833,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15868,7 +15868,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15899,7 +15899,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -15934,7 +15934,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -15969,7 +15969,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -16008,7 +16008,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleFinishTimer
 		}{
@@ -16023,7 +16023,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -16042,7 +16042,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleFinishTimer
@@ -16061,7 +16061,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16084,7 +16084,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleFinishTimer
 			api.VehicleRange
@@ -16103,7 +16103,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -16126,7 +16126,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleFinishTimer
@@ -16149,7 +16149,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16176,7 +16176,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -16195,7 +16195,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -16218,7 +16218,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleFinishTimer
@@ -16241,7 +16241,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16268,7 +16268,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleFinishTimer
 			api.VehicleOdometer
@@ -16291,7 +16291,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleFinishTimer
@@ -16318,7 +16318,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleFinishTimer
@@ -16345,7 +16345,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16376,7 +16376,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -16395,7 +16395,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -16418,7 +16418,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -16441,7 +16441,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16468,7 +16468,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -16491,7 +16491,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -16518,7 +16518,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -16545,7 +16545,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16576,7 +16576,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -16599,7 +16599,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -16626,7 +16626,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -16653,7 +16653,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16684,7 +16684,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.VehicleClimater
 			api.VehicleFinishTimer
@@ -16711,7 +16711,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.SocLimiter
 			api.VehicleClimater
@@ -16742,7 +16742,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.VehicleClimater
@@ -16773,7 +16773,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.SocLimiter
@@ -16808,7 +16808,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -16827,7 +16827,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -16850,7 +16850,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -16873,7 +16873,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -16900,7 +16900,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -16923,7 +16923,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -16950,7 +16950,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -16977,7 +16977,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17008,7 +17008,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -17031,7 +17031,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -17058,7 +17058,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17085,7 +17085,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17116,7 +17116,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleFinishTimer
@@ -17143,7 +17143,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -17174,7 +17174,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17205,7 +17205,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17240,7 +17240,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -17263,7 +17263,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -17290,7 +17290,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17317,7 +17317,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17348,7 +17348,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -17375,7 +17375,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -17406,7 +17406,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17437,7 +17437,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17472,7 +17472,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -17499,7 +17499,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -17530,7 +17530,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17561,7 +17561,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17596,7 +17596,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.VehicleClimater
@@ -17627,7 +17627,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.SocLimiter
@@ -17662,7 +17662,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17697,7 +17697,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -17736,7 +17736,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -17755,7 +17755,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -17778,7 +17778,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -17801,7 +17801,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -17828,7 +17828,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -17851,7 +17851,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -17878,7 +17878,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -17905,7 +17905,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -17936,7 +17936,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -17959,7 +17959,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -17986,7 +17986,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18013,7 +18013,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18044,7 +18044,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleFinishTimer
@@ -18071,7 +18071,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -18102,7 +18102,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18133,7 +18133,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18168,7 +18168,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -18191,7 +18191,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -18218,7 +18218,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18245,7 +18245,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18276,7 +18276,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -18303,7 +18303,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -18334,7 +18334,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18365,7 +18365,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18400,7 +18400,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -18427,7 +18427,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -18458,7 +18458,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18489,7 +18489,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18524,7 +18524,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.VehicleClimater
@@ -18555,7 +18555,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.SocLimiter
@@ -18590,7 +18590,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18625,7 +18625,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -18664,7 +18664,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -18687,7 +18687,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -18714,7 +18714,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -18741,7 +18741,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -18772,7 +18772,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -18799,7 +18799,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -18830,7 +18830,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -18861,7 +18861,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -18896,7 +18896,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -18923,7 +18923,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -18954,7 +18954,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -18985,7 +18985,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19020,7 +19020,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19051,7 +19051,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19086,7 +19086,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19121,7 +19121,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19160,7 +19160,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19187,7 +19187,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19218,7 +19218,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19249,7 +19249,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19284,7 +19284,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19315,7 +19315,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19350,7 +19350,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19385,7 +19385,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19424,7 +19424,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19455,7 +19455,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19490,7 +19490,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19525,7 +19525,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19564,7 +19564,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19599,7 +19599,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -19638,7 +19638,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19677,7 +19677,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector == nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -19720,7 +19720,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 		}{
@@ -19735,7 +19735,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -19754,7 +19754,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -19773,7 +19773,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -19796,7 +19796,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleRange
@@ -19815,7 +19815,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -19838,7 +19838,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -19861,7 +19861,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -19888,7 +19888,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleOdometer
@@ -19907,7 +19907,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -19930,7 +19930,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -19953,7 +19953,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -19980,7 +19980,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleOdometer
@@ -20003,7 +20003,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -20030,7 +20030,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20057,7 +20057,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20088,7 +20088,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -20107,7 +20107,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -20130,7 +20130,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20153,7 +20153,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20180,7 +20180,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -20203,7 +20203,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -20230,7 +20230,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20257,7 +20257,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20288,7 +20288,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -20311,7 +20311,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -20338,7 +20338,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20365,7 +20365,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20396,7 +20396,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -20423,7 +20423,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -20454,7 +20454,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20485,7 +20485,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -20520,7 +20520,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20539,7 +20539,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20562,7 +20562,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20585,7 +20585,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20612,7 +20612,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20635,7 +20635,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20662,7 +20662,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20689,7 +20689,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20720,7 +20720,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20743,7 +20743,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20770,7 +20770,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20797,7 +20797,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20828,7 +20828,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20855,7 +20855,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20886,7 +20886,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20917,7 +20917,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -20952,7 +20952,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -20975,7 +20975,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21002,7 +21002,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21029,7 +21029,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21060,7 +21060,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21087,7 +21087,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21118,7 +21118,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21149,7 +21149,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21184,7 +21184,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21211,7 +21211,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21242,7 +21242,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21273,7 +21273,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21308,7 +21308,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21339,7 +21339,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -21374,7 +21374,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21409,7 +21409,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -21448,7 +21448,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21467,7 +21467,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21490,7 +21490,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21513,7 +21513,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21540,7 +21540,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21563,7 +21563,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21590,7 +21590,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21617,7 +21617,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21648,7 +21648,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21671,7 +21671,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21698,7 +21698,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21725,7 +21725,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21756,7 +21756,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21783,7 +21783,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21814,7 +21814,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21845,7 +21845,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21880,7 +21880,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21903,7 +21903,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -21930,7 +21930,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21957,7 +21957,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -21988,7 +21988,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -22015,7 +22015,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -22046,7 +22046,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -22077,7 +22077,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -22112,7 +22112,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -22139,7 +22139,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -22170,7 +22170,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -22201,7 +22201,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -22236,7 +22236,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -22267,7 +22267,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -22302,7 +22302,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -22337,7 +22337,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -22376,7 +22376,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22399,7 +22399,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22426,7 +22426,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22453,7 +22453,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22484,7 +22484,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22511,7 +22511,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22542,7 +22542,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22573,7 +22573,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22608,7 +22608,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22635,7 +22635,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22666,7 +22666,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22697,7 +22697,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22732,7 +22732,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22763,7 +22763,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22798,7 +22798,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22833,7 +22833,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22872,7 +22872,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22899,7 +22899,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -22930,7 +22930,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22961,7 +22961,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -22996,7 +22996,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -23027,7 +23027,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -23062,7 +23062,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -23097,7 +23097,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -23136,7 +23136,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -23167,7 +23167,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -23202,7 +23202,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -23237,7 +23237,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -23276,7 +23276,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -23311,7 +23311,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -23350,7 +23350,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -23389,7 +23389,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer == nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -23432,7 +23432,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -23451,7 +23451,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -23474,7 +23474,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23497,7 +23497,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23524,7 +23524,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -23547,7 +23547,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -23574,7 +23574,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23601,7 +23601,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23632,7 +23632,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -23655,7 +23655,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -23682,7 +23682,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23709,7 +23709,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23740,7 +23740,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleFinishTimer
@@ -23767,7 +23767,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -23798,7 +23798,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23829,7 +23829,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23864,7 +23864,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -23887,7 +23887,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -23914,7 +23914,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23941,7 +23941,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -23972,7 +23972,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -23999,7 +23999,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -24030,7 +24030,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -24061,7 +24061,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -24096,7 +24096,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -24123,7 +24123,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -24154,7 +24154,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -24185,7 +24185,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -24220,7 +24220,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.VehicleClimater
@@ -24251,7 +24251,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.Resurrector
 			api.SocLimiter
@@ -24286,7 +24286,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -24321,7 +24321,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.Resurrector
@@ -24360,7 +24360,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24383,7 +24383,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24410,7 +24410,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24437,7 +24437,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24468,7 +24468,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24495,7 +24495,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24526,7 +24526,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24557,7 +24557,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24592,7 +24592,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24619,7 +24619,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24650,7 +24650,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24681,7 +24681,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24716,7 +24716,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24747,7 +24747,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24782,7 +24782,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24817,7 +24817,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24856,7 +24856,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24883,7 +24883,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -24914,7 +24914,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24945,7 +24945,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -24980,7 +24980,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -25011,7 +25011,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -25046,7 +25046,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -25081,7 +25081,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -25120,7 +25120,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -25151,7 +25151,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -25186,7 +25186,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -25221,7 +25221,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -25260,7 +25260,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -25295,7 +25295,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.Resurrector
@@ -25334,7 +25334,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -25373,7 +25373,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter == nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -25416,7 +25416,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25439,7 +25439,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25466,7 +25466,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25493,7 +25493,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25524,7 +25524,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25551,7 +25551,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25582,7 +25582,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25613,7 +25613,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25648,7 +25648,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25675,7 +25675,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25706,7 +25706,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25737,7 +25737,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25772,7 +25772,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25803,7 +25803,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25838,7 +25838,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25873,7 +25873,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -25912,7 +25912,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25939,7 +25939,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -25970,7 +25970,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26001,7 +26001,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26036,7 +26036,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -26067,7 +26067,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -26102,7 +26102,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26137,7 +26137,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26176,7 +26176,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -26207,7 +26207,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -26242,7 +26242,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26277,7 +26277,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26316,7 +26316,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -26351,7 +26351,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentGetter
 			api.Resurrector
@@ -26390,7 +26390,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26429,7 +26429,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController == nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentGetter
@@ -26472,7 +26472,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26499,7 +26499,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26530,7 +26530,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26561,7 +26561,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26596,7 +26596,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26627,7 +26627,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26662,7 +26662,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26697,7 +26697,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26736,7 +26736,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26767,7 +26767,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26802,7 +26802,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26837,7 +26837,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26876,7 +26876,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26911,7 +26911,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -26950,7 +26950,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -26989,7 +26989,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater == nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27032,7 +27032,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27063,7 +27063,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27098,7 +27098,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27133,7 +27133,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27172,7 +27172,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27207,7 +27207,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27246,7 +27246,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27285,7 +27285,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer == nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27328,7 +27328,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27363,7 +27363,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27402,7 +27402,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27441,7 +27441,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange == nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27484,7 +27484,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27523,7 +27523,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState == nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.CurrentController
 			api.CurrentGetter
@@ -27566,7 +27566,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter == nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController
@@ -27609,7 +27609,7 @@ func decorateVehicle(base api.Vehicle, socLimiter func() (int64, error), chargeS
 
 	case chargeController != nil && chargeState != nil && currentController != nil && currentGetter != nil && resurrector != nil && socLimiter != nil && vehicleClimater != nil && vehicleFinishTimer != nil && vehicleOdometer != nil && vehicleRange != nil:
 		return &struct {
-			api.Vehicle
+			*Vehicle
 			api.ChargeController
 			api.ChargeState
 			api.CurrentController